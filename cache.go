@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nextzhou/goderive/plugin"
+	"github.com/nextzhou/goderive/utils"
+	"github.com/spf13/cobra"
+)
+
+// Cache stores generated source of packages, keyed by the hash of their input files.
+type Cache struct {
+	Dir string
+}
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/goderive, or ~/.cache/goderive if XDG_CACHE_HOME is not set.
+// It returns an empty string if neither of them is available.
+func DefaultCacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "goderive")
+	}
+	if home := os.Getenv("HOME"); home != "" {
+		return filepath.Join(home, ".cache", "goderive")
+	}
+	return ""
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.Dir, key[:2], key)
+}
+
+// Get returns the cached source of key, ok is false if there is no such entry.
+func (c *Cache) Get(key string) (src []byte, ok bool) {
+	src, err := ioutil.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil, false
+	}
+	return src, true
+}
+
+// Put stores src as the entry of key.
+func (c *Cache) Put(key string, src []byte) error {
+	filename := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	// entries may be written by concurrent goderive processes
+	tmpName, err := utils.WriteTempFile(filename, src, 0644)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, filename); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// Clean removes all entries.
+func (c *Cache) Clean() error {
+	return os.RemoveAll(c.Dir)
+}
+
+// cachedOutput is an output file of a package in cache entries
+type cachedOutput struct {
+	Name string `json:"name"`
+	Src  []byte `json:"src"`
+}
+
+// getCachedOutputs returns the generated files of the package in path from cache, ok is false if there is no such entry.
+func (d *Derive) getCachedOutputs(path, key string) (files []GeneratedFile, ok bool) {
+	entry, ok := d.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	var outputs []cachedOutput
+	if err := json.Unmarshal(entry, &outputs); err != nil {
+		return nil, false
+	}
+	for _, output := range outputs {
+		files = append(files, GeneratedFile{Path: path, Filename: filepath.Join(path, output.Name), Src: output.Src})
+	}
+	return files, true
+}
+
+// putCachedOutputs stores the generated files of a package in cache, files to be deleted are not stored.
+func (d *Derive) putCachedOutputs(key string, files []GeneratedFile) error {
+	outputs := make([]cachedOutput, 0, len(files))
+	for _, file := range files {
+		if file.Src != nil {
+			outputs = append(outputs, cachedOutput{Name: filepath.Base(file.Filename), Src: file.Src})
+		}
+	}
+	entry, err := json.Marshal(outputs)
+	if err != nil {
+		return err
+	}
+	return d.cache.Put(key, entry)
+}
+
+// cacheSalt returns the data that all cache keys depend on besides the input files.
+func (d *Derive) cacheSalt() []byte {
+	var descriptions []string
+	d.Plugins.ForEach(func(plg plugin.Plugin) {
+		descriptions = append(descriptions, plg.Describe().ToHelpString())
+	})
+	sort.Strings(descriptions)
+	ctx := d.BuildContext()
+	// the cache is disabled if plugins are filtered
+	salt := []byte(fmt.Sprintf("goderive %s\n%s %s %v\n%q %v\n", Version, ctx.GOOS, ctx.GOARCH, ctx.BuildTags,
+		d.Opts, d.OptsOverride))
+	if Version == "UNKNOWN" {
+		// development builds share the version, tell them apart by the executable
+		if exe, err := os.Executable(); err == nil {
+			if stat, err := os.Stat(exe); err == nil {
+				salt = append(salt, fmt.Sprintf("%s %d %d\n", exe, stat.Size(), stat.ModTime().UnixNano())...)
+			}
+		}
+	}
+	for _, desc := range descriptions {
+		salt = append(salt, desc...)
+		salt = append(salt, 0)
+	}
+	return salt
+}
+
+// packageCacheKey returns the cache key of a package consisting of given files.
+func (d *Derive) packageCacheKey(salt []byte, files []string) (string, error) {
+	files = append([]string(nil), files...)
+	sort.Strings(files)
+	h := sha256.New()
+	h.Write(salt)
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", err
+		}
+		stat, err := f.Stat()
+		if err == nil {
+			fmt.Fprintf(h, "%s\x00%d\x00", filepath.Base(file), stat.Size())
+			_, err = io.Copy(h, f)
+		}
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// defaultHelpTemplate is the help template of cobra, which subcommands should use instead of the one of goderive.
+var defaultHelpTemplate = new(cobra.Command).HelpTemplate()
+
+func newCacheCommand() *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of generated packages",
+	}
+	cmd.SetHelpTemplate(defaultHelpTemplate)
+	clean := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove all cached packages",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				dir = DefaultCacheDir()
+			}
+			if dir == "" {
+				return fmt.Errorf("cache directory is unknown, neither XDG_CACHE_HOME nor HOME is set")
+			}
+			return (&Cache{Dir: dir}).Clean()
+		},
+		SilenceUsage: true,
+	}
+	clean.Flags().StringVar(&dir, "cache-dir", "", "cache directory, $XDG_CACHE_HOME/goderive by default")
+	cmd.AddCommand(clean)
+	return cmd
+}
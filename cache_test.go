@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nextzhou/goderive/plugin/set"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCache(t *testing.T) {
+	Convey("cache", t, func() {
+		dir, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		derive := NewDerive()
+		cache := &Cache{Dir: filepath.Join(dir, "cache")}
+
+		file := filepath.Join(dir, "foo.go")
+		So(ioutil.WriteFile(file, []byte("package foo\n"), 0644), ShouldBeNil)
+		key, err := derive.packageCacheKey(derive.cacheSalt(), []string{file})
+		So(err, ShouldBeNil)
+
+		Convey("get and put", func() {
+			_, ok := cache.Get(key)
+			So(ok, ShouldBeFalse)
+			So(cache.Put(key, []byte("src")), ShouldBeNil)
+			src, ok := cache.Get(key)
+			So(ok, ShouldBeTrue)
+			So(string(src), ShouldEqual, "src")
+
+			So(cache.Clean(), ShouldBeNil)
+			_, ok = cache.Get(key)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("key changes with input", func() {
+			So(ioutil.WriteFile(file, []byte("package bar\n"), 0644), ShouldBeNil)
+			newKey, err := derive.packageCacheKey(derive.cacheSalt(), []string{file})
+			So(err, ShouldBeNil)
+			So(newKey, ShouldNotEqual, key)
+
+			derive.RegisterPlugin(brokenPlugin{})
+			saltedKey, err := derive.packageCacheKey(derive.cacheSalt(), []string{file})
+			So(err, ShouldBeNil)
+			So(saltedKey, ShouldNotEqual, newKey)
+		})
+	})
+}
+
+func TestRunCache(t *testing.T) {
+	Convey("run with cache", t, func() {
+		dir, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+		derive.reporter, err = NewReporter("text", ioutil.Discard, ioutil.Discard)
+		So(err, ShouldBeNil)
+		derive.cache = &Cache{Dir: filepath.Join(dir, "cache")}
+		pkgDir := filepath.Join(dir, "foo")
+		So(os.MkdirAll(pkgDir, 0755), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(pkgDir, "foo.go"), []byte("package foo\n\n// derive-set\ntype Foo int\n"), 0644), ShouldBeNil)
+		cached := func() bool {
+			entries, _ := ioutil.ReadDir(derive.cache.Dir)
+			return len(entries) > 0
+		}
+
+		Convey("check runs don't update the cache", func() {
+			derive.Check = true
+			So(derive.Run([]string{pkgDir}), ShouldNotBeNil)
+			So(cached(), ShouldBeFalse)
+		})
+
+		Convey("generating runs update the cache", func() {
+			So(derive.Run([]string{pkgDir}), ShouldBeNil)
+			So(cached(), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nextzhou/goderive/utils"
+	"github.com/spf13/cobra"
+)
+
+// invalidSuffix is appended to the output name of invalid generated code kept for debugging
+const invalidSuffix = ".invalid"
+
+// ListOutputFiles returns files generated by goderive in path, walking the same tree as ListGoFiles.
+// Outputs of all build constraints are listed, as well as invalid code kept for debugging.
+func (d *Derive) ListOutputFiles(path string, recursive bool) ([]string, error) {
+	if strings.HasSuffix(path, "/...") {
+		recursive = true
+		path = strings.TrimSuffix(path, "/...")
+	}
+	stat, err := os.Stat(path)
+	if os.IsNotExist(err) && isImportPath(path) {
+		if path, err = d.ResolveImportPath(path); err != nil {
+			return nil, err
+		}
+		stat, err = os.Stat(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		settings, err := d.Settings(filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+		if !isGeneratedOutput(settings, path) {
+			return nil, fmt.Errorf("%#v is not a file generated by goderive", path)
+		}
+		return []string{path}, nil
+	}
+
+	settings, err := d.Settings(path)
+	if err != nil {
+		return nil, err
+	}
+	dirInfo, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range dirInfo {
+		filename := filepath.Join(path, entry.Name())
+		if !entry.IsDir() {
+			if isGeneratedOutput(settings, filename) {
+				files = append(files, filename)
+			}
+			continue
+		}
+		if recursive && !settings.Skip(filename, true) && !skipSubDir(path, entry.Name()) {
+			subDirFiles, err := d.ListOutputFiles(filename, recursive)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, subDirFiles...)
+		}
+	}
+	return files, nil
+}
+
+// isGeneratedOutput reports whether the file is named as an output and starts with the header of goderive.
+func isGeneratedOutput(settings *Settings, filename string) bool {
+	if !settings.IsOutputName(strings.TrimSuffix(filepath.Base(filename), invalidSuffix)) {
+		return false
+	}
+	src, err := ioutil.ReadFile(filename)
+	return err == nil && utils.IsGeneratedFile(src)
+}
+
+// Clean removes files generated by goderive in inputPaths, and prints them to w.
+// Nothing is removed if dryRun. Files are removed as many as possible, errors are returned at last.
+func (d *Derive) Clean(w io.Writer, inputPaths []string, dryRun bool) utils.ErrorList {
+	if len(inputPaths) == 0 {
+		inputPaths = []string{"."}
+	}
+	var errs utils.ErrorList
+	files := utils.NewStrSet(0)
+	for _, path := range inputPaths {
+		fs, err := d.ListOutputFiles(path, false)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		files.Append(fs...)
+	}
+	files.ForEach(func(file string) {
+		if dryRun {
+			fmt.Fprintf(w, "would remove %s\n", displayPath(file))
+			return
+		}
+		if err := os.Remove(file); err != nil {
+			errs.Add(err)
+			return
+		}
+		fmt.Fprintf(w, "removed %s\n", displayPath(file))
+	})
+	errs.Sort()
+	return errs
+}
+
+func (d *Derive) newCleanCommand() *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "clean [path ...]",
+		Short: "Remove files generated by goderive",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			errs := d.Clean(os.Stdout, args, dryRun)
+			reporter, _ := NewReporter(FormatText, os.Stderr, os.Stderr)
+			for _, err := range errs {
+				reporter.Diagnostic(err, utils.SeverityError)
+			}
+			if len(errs) > 0 {
+				return fmt.Errorf("%d error(s) found", len(errs))
+			}
+			return nil
+		},
+		SilenceUsage: true,
+	}
+	cmd.SetHelpTemplate(defaultHelpTemplate)
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "print files to be removed without removing them")
+	return cmd
+}
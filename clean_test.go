@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nextzhou/goderive/utils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClean(t *testing.T) {
+	Convey("clean", t, func() {
+		root, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+		sub := filepath.Join(root, "sub")
+		So(os.MkdirAll(filepath.Join(root, "testdata"), 0755), ShouldBeNil)
+		So(os.MkdirAll(sub, 0755), ShouldBeNil)
+		generated := []byte(utils.HeaderComment + "package m\n")
+		files := map[string][]byte{
+			"go.mod":                  []byte("module example.com/m\n"),
+			"m.go":                    []byte("package m\n"),
+			"derived.gen.go":          generated,
+			"derived_linux.gen.go":    generated,
+			"derived.gen_test.go":     generated,
+			"derived.gen.go.invalid":  generated,
+			"handwritten.gen.go":      []byte("package m\n"),
+			"mock.gen.go":             generated,
+			"testdata/derived.gen.go": generated,
+			"sub/derived.gen.go":      generated,
+		}
+		for name, src := range files {
+			So(ioutil.WriteFile(filepath.Join(root, filepath.FromSlash(name)), src, 0644), ShouldBeNil)
+		}
+		exists := func(name string) bool {
+			_, err := os.Stat(filepath.Join(root, filepath.FromSlash(name)))
+			return err == nil
+		}
+
+		derive := NewDerive()
+
+		Convey("dry run", func() {
+			var buf bytes.Buffer
+			So(derive.Clean(&buf, []string{root + "/..."}, true), ShouldBeEmpty)
+			So(buf.String(), ShouldContainSubstring, "would remove ")
+			So(bytes.Count(buf.Bytes(), []byte("\n")), ShouldEqual, 5)
+			So(exists("derived.gen.go"), ShouldBeTrue)
+		})
+
+		Convey("generated outputs only", func() {
+			So(derive.Clean(new(bytes.Buffer), []string{root}, false), ShouldBeEmpty)
+			So(exists("derived.gen.go"), ShouldBeFalse)
+			So(exists("derived_linux.gen.go"), ShouldBeFalse)
+			So(exists("derived.gen_test.go"), ShouldBeFalse)
+			So(exists("derived.gen.go.invalid"), ShouldBeFalse)
+			So(exists("handwritten.gen.go"), ShouldBeTrue)
+			So(exists("mock.gen.go"), ShouldBeTrue)
+			So(exists("m.go"), ShouldBeTrue)
+			So(exists("sub/derived.gen.go"), ShouldBeTrue)
+		})
+
+		Convey("recursive", func() {
+			So(derive.Clean(new(bytes.Buffer), []string{root + "/..."}, false), ShouldBeEmpty)
+			So(exists("sub/derived.gen.go"), ShouldBeFalse)
+			So(exists("testdata/derived.gen.go"), ShouldBeTrue)
+		})
+
+		Convey("not generated file", func() {
+			errs := derive.Clean(new(bytes.Buffer), []string{filepath.Join(root, "m.go")}, false)
+			So(errs, ShouldHaveLength, 1)
+			So(exists("m.go"), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nextzhou/goderive/plugin"
+	"github.com/nextzhou/goderive/utils"
+)
+
+const ConfigFileName = ".goderive.json"
+
+// Config is the content of a configuration file, fields absent in the file are left nil.
+//
+//	{
+//	  "output": "derived.gen.go",
+//	  "output_mode": "package",
+//	  "output_pattern": "{name}_derived.gen.go",
+//	  "exclude_dirs": ["vendor"],
+//	  "exclude_exts": [".gen.go", ".gen_test.go"],
+//	  "include": ["internal/**"],
+//	  "exclude": ["**/mocks", "testdata/**/*.go"],
+//	  "gitignore": true,
+//	  "delete": true,
+//	  "plugins": {"set": "Order=Append"}
+//	}
+type Config struct {
+	Output        *string  `json:"output"`
+	OutputMode    *string  `json:"output_mode"`
+	OutputPattern *string  `json:"output_pattern"`
+	ExcludeDirs   []string `json:"exclude_dirs"`
+	ExcludeExts   []string `json:"exclude_exts"`
+	// doublestar globs relative to the module root
+	Include   []string `json:"include"`
+	Exclude   []string `json:"exclude"`
+	GitIgnore *bool    `json:"gitignore"`
+	Delete    *bool    `json:"delete"`
+	// default options of plugins, in the format of derive comment
+	Plugins map[string]string `json:"plugins"`
+}
+
+// LoadConfig reads and parses the configuration file.
+func LoadConfig(filename string) (*Config, error) {
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	config := new(Config)
+	if err := json.Unmarshal(src, config); err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			// the offending byte is the last one read
+			return nil, utils.Positioned(err, offsetPosition(filename, src, int(syntaxErr.Offset)-1), "", "")
+		}
+		return nil, fmt.Errorf("%s: %s", filename, err.Error())
+	}
+	return config, nil
+}
+
+func offsetPosition(filename string, src []byte, offset int) token.Position {
+	if offset > len(src) {
+		offset = len(src)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	lineStart := bytes.LastIndexByte(src[:offset], '\n') + 1
+	return token.Position{
+		Filename: filename,
+		Offset:   offset,
+		Line:     bytes.Count(src[:offset], []byte("\n")) + 1,
+		Column:   offset - lineStart + 1,
+	}
+}
+
+// Settings are options taking effect in a directory, resolved from flags and configuration files.
+// Flags set in command line take precedence over configuration files, and nearer files over farther ones.
+type Settings struct {
+	Output        string
+	OutputMode    string
+	OutputPattern string
+	Delete        bool
+	ExcludeDirs   *utils.StrSet
+	ExcludeExts   *utils.StrSet
+	// doublestar globs of paths relative to Root
+	Includes []string
+	Excludes []string
+	// whether .gitignore files are respected, and the ones from Root to the directory, collected even if not
+	GitIgnore  bool
+	GitIgnores []*utils.GitIgnore
+	// module root, or working directory out of modules
+	Root string
+	// default options of plugins, annotations take precedence
+	PluginOptions map[string]*plugin.Options
+	// configuration files in effect, from the farthest one
+	ConfigFiles []string
+}
+
+// ExcludePath reports whether the name is excluded by exclude-dir or exclude-ext. Hidden names are always excluded.
+func (s *Settings) ExcludePath(name string, isDir bool) bool {
+	// skip ".", ".." and hidden file/dir
+	if name[0] == '.' {
+		return true
+	}
+	if isDir {
+		return s.ExcludeDirs.Contains(name)
+	} else {
+		return s.ExcludeExts.Any(func(ext string) bool { return strings.HasSuffix(name, ext) })
+	}
+}
+
+// Skip reports whether the file or directory in path is left out of scanning, by ExcludePath, exclude and include
+// patterns and .gitignore files. Hidden files and directories are scanned only if they match an include pattern
+// naming them explicitly, i.e. the last element of the pattern starts with a dot.
+func (s *Settings) Skip(path string, isDir bool) bool {
+	rel := path
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+		if rel, err = filepath.Rel(s.Root, absPath); err == nil {
+			rel = filepath.ToSlash(rel)
+		}
+	}
+	if matchAnyGlob(s.Excludes, rel) {
+		return true
+	}
+	for i := len(s.GitIgnores) - 1; i >= 0 && s.GitIgnore; i-- {
+		if matched, ignored := s.GitIgnores[i].Match(path, isDir); matched {
+			if ignored {
+				return true
+			}
+			break
+		}
+	}
+	if s.ExcludePath(filepath.Base(path), isDir) {
+		if filepath.Base(path)[0] != '.' {
+			return true
+		}
+		for _, pattern := range s.Includes {
+			if strings.HasPrefix(pathpkg.Base(pattern), ".") && utils.MatchGlob(pattern, rel) {
+				return false
+			}
+		}
+		return true
+	}
+	if isDir || len(s.Includes) == 0 {
+		return false
+	}
+	// a file is included if it or any of its directories matches
+	for ; rel != "." && rel != "/" && rel != ""; rel = pathpkg.Dir(rel) {
+		if matchAnyGlob(s.Includes, rel) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if utils.MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// baseSettings returns settings from flags only.
+func (d *Derive) baseSettings() *Settings {
+	return &Settings{
+		Output:        d.Output,
+		OutputMode:    d.OutputMode,
+		OutputPattern: d.OutputPattern,
+		Delete:        d.Delete,
+		ExcludeDirs:   utils.NewStrSetFromSlice(d.ExcludeDirs),
+		ExcludeExts:   utils.NewStrSetFromSlice(d.ExcludeExts),
+		Includes:      d.Includes,
+		Excludes:      d.Excludes,
+		GitIgnore:     d.GitIgnore,
+		PluginOptions: make(map[string]*plugin.Options),
+	}
+}
+
+// Settings returns settings of the directory, configuration files are searched from it up to the module root.
+func (d *Derive) Settings(dir string) (*Settings, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	d.settingsMu.Lock()
+	settings, ok := d.settings[dir]
+	d.settingsMu.Unlock()
+	if ok {
+		return settings, nil
+	}
+
+	// module root or file system root
+	parentDir := filepath.Dir(dir)
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		settings = d.baseSettings()
+		settings.Root = dir
+	} else if parentDir == dir {
+		settings = d.baseSettings()
+		if settings.Root, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	} else if settings, err = d.Settings(parentDir); err != nil {
+		return nil, err
+	}
+	configFile := filepath.Join(dir, ConfigFileName)
+	if _, err := os.Stat(configFile); err == nil {
+		config, err := LoadConfig(configFile)
+		if err != nil {
+			return nil, err
+		}
+		if settings, err = d.applyConfig(settings, configFile, config); err != nil {
+			return nil, err
+		}
+	}
+	// a nearer configuration file may enable gitignore, so .gitignore files are always collected
+	if src, err := ioutil.ReadFile(filepath.Join(dir, ".gitignore")); err == nil {
+		withIgnore := *settings
+		withIgnore.GitIgnores = append(append([]*utils.GitIgnore(nil), settings.GitIgnores...), utils.ParseGitIgnore(dir, src))
+		settings = &withIgnore
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	d.settingsMu.Lock()
+	if d.settings == nil {
+		d.settings = make(map[string]*Settings)
+	}
+	d.settings[dir] = settings
+	d.settingsMu.Unlock()
+	return settings, nil
+}
+
+// applyConfig returns settings of parent overridden by config, except the flags set in command line.
+func (d *Derive) applyConfig(parent *Settings, configFile string, config *Config) (*Settings, error) {
+	settings := *parent
+	settings.ConfigFiles = append(append([]string(nil), parent.ConfigFiles...), configFile)
+	flags := d.Cmd.Flags()
+	if config.Output != nil && !flags.Changed("output") {
+		settings.Output = *config.Output
+	}
+	if config.OutputMode != nil && !flags.Changed("output-mode") {
+		if err := validateOutputMode(*config.OutputMode); err != nil {
+			return nil, fmt.Errorf("%s: %s", configFile, err.Error())
+		}
+		settings.OutputMode = *config.OutputMode
+	}
+	if config.OutputPattern != nil && !flags.Changed("output-pattern") {
+		if err := validateOutputPattern(*config.OutputPattern); err != nil {
+			return nil, fmt.Errorf("%s: %s", configFile, err.Error())
+		}
+		settings.OutputPattern = *config.OutputPattern
+	}
+	if config.Delete != nil && !flags.Changed("delete") {
+		settings.Delete = *config.Delete
+	}
+	if config.ExcludeDirs != nil && !flags.Changed("exclude-dir") {
+		settings.ExcludeDirs = utils.NewStrSetFromSlice(config.ExcludeDirs)
+	}
+	if config.ExcludeExts != nil && !flags.Changed("exclude-ext") {
+		settings.ExcludeExts = utils.NewStrSetFromSlice(config.ExcludeExts)
+	}
+	if config.Include != nil && !flags.Changed("include") {
+		if err := validateGlobs(config.Include); err != nil {
+			return nil, fmt.Errorf("%s: %s", configFile, err.Error())
+		}
+		settings.Includes = config.Include
+	}
+	if config.Exclude != nil && !flags.Changed("exclude") {
+		if err := validateGlobs(config.Exclude); err != nil {
+			return nil, fmt.Errorf("%s: %s", configFile, err.Error())
+		}
+		settings.Excludes = config.Exclude
+	}
+	if config.GitIgnore != nil && !flags.Changed("gitignore") {
+		settings.GitIgnore = *config.GitIgnore
+	}
+
+	settings.PluginOptions = make(map[string]*plugin.Options)
+	for pluginID, opts := range parent.PluginOptions {
+		settings.PluginOptions[pluginID] = opts
+	}
+	pluginIDs := make([]string, 0, len(config.Plugins))
+	for pluginID := range config.Plugins {
+		pluginIDs = append(pluginIDs, pluginID)
+	}
+	sort.Strings(pluginIDs)
+	for _, pluginID := range pluginIDs {
+		if _, err := d.GetPlugin(pluginID); err != nil {
+			return nil, fmt.Errorf("%s: %s", configFile, err.Error())
+		}
+		configOpts, err := plugin.ParseOptions(config.Plugins[pluginID])
+		if err != nil {
+			return nil, fmt.Errorf("%s: plugin %s: %s", configFile, pluginID, err.Error())
+		}
+		opts := plugin.NewOptions()
+		opts.MergeDefault(configOpts)
+		opts.MergeDefault(parent.PluginOptions[pluginID])
+		settings.PluginOptions[pluginID] = opts
+	}
+	return &settings, nil
+}
+
+func validateGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		if err := utils.ValidateGlob(pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
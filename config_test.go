@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nextzhou/goderive/plugin"
+	"github.com/nextzhou/goderive/plugin/set"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSettings(t *testing.T) {
+	Convey("settings", t, func() {
+		root, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+		sub := filepath.Join(root, "internal", "sub")
+		So(os.MkdirAll(sub, 0755), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(root, ConfigFileName), []byte(`{"output": "gen.go", "exclude_dirs": ["testdata"]}`), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(root, "internal", ConfigFileName), []byte(`{"delete": false, "plugins": {"set": "Order=Append; Export"}}`), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(sub, ConfigFileName), []byte(`{"plugins": {"set": "!Export"}}`), 0644), ShouldBeNil)
+
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+
+		Convey("merged from outer configuration files", func() {
+			settings, err := derive.Settings(sub)
+			So(err, ShouldBeNil)
+			So(settings.Output, ShouldEqual, "gen.go")
+			So(settings.Delete, ShouldBeFalse)
+			So(settings.ExcludePath("testdata", true), ShouldBeTrue)
+			So(settings.ExcludePath("vendor", true), ShouldBeFalse)
+			So(settings.ConfigFiles, ShouldHaveLength, 3)
+
+			opts, err := plugin.ParseOptions("Order=Key")
+			So(err, ShouldBeNil)
+			opts.MergeDefault(settings.PluginOptions["set"])
+			So(opts.Args["Order"].Values, ShouldResemble, []plugin.Value{"Key"})
+			So(opts.Flags[plugin.Flag("Export")].IsFalse(), ShouldBeTrue)
+		})
+
+		Convey("flags take precedence", func() {
+			So(derive.Cmd.Flags().Set("output", "flag.gen.go"), ShouldBeNil)
+			settings, err := derive.Settings(root)
+			So(err, ShouldBeNil)
+			So(settings.Output, ShouldEqual, "flag.gen.go")
+		})
+
+		Convey("skip paths", func() {
+			So(ioutil.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.pb.go\n"), 0644), ShouldBeNil)
+			So(ioutil.WriteFile(filepath.Join(sub, ConfigFileName), []byte(`{"include": ["internal/**", ".proto"], "exclude": ["**/mocks"], "gitignore": true}`), 0644), ShouldBeNil)
+			settings, err := derive.Settings(sub)
+			So(err, ShouldBeNil)
+			So(settings.Root, ShouldEqual, root)
+			So(settings.GitIgnores, ShouldHaveLength, 1)
+			So(settings.Skip(filepath.Join(sub, "x.go"), false), ShouldBeFalse)
+			So(settings.Skip(filepath.Join(sub, "mocks"), true), ShouldBeTrue)
+			So(settings.Skip(filepath.Join(sub, "x.pb.go"), false), ShouldBeTrue)
+			So(settings.Skip(filepath.Join(sub, "testdata"), true), ShouldBeTrue)
+			So(settings.Skip(filepath.Join(sub, ".git"), true), ShouldBeTrue)
+			So(settings.Skip(filepath.Join(root, ".proto"), true), ShouldBeFalse)
+			So(settings.Skip(filepath.Join(root, "x.go"), false), ShouldBeTrue)
+
+			So(ioutil.WriteFile(filepath.Join(sub, ConfigFileName), []byte(`{"exclude": ["["]}`), 0644), ShouldBeNil)
+			_, err = NewDerive().Settings(sub)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("invalid configuration", func() {
+			So(ioutil.WriteFile(filepath.Join(sub, ConfigFileName), []byte("{\n  \"output\" \"x\"\n}"), 0644), ShouldBeNil)
+			_, err := derive.Settings(sub)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, filepath.Join(sub, ConfigFileName)+":2:12: ")
+
+			So(ioutil.WriteFile(filepath.Join(sub, ConfigFileName), []byte(`{"plugins": {"unknown": ""}}`), 0644), ShouldBeNil)
+			_, err = derive.Settings(sub)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
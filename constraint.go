@@ -0,0 +1,90 @@
+package main
+
+import (
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+)
+
+// GOOS and GOARCH recognized in file name suffixes, see go/build
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+		"illumos": true, "ios": true, "js": true, "linux": true, "nacl": true, "netbsd": true, "openbsd": true,
+		"plan9": true, "solaris": true, "wasip1": true, "windows": true, "zos": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true, "arm64be": true,
+		"loong64": true, "mips": true, "mipsle": true, "mips64": true, "mips64le": true, "mips64p32": true,
+		"mips64p32le": true, "ppc": true, "ppc64": true, "ppc64le": true, "riscv": true, "riscv64": true,
+		"s390": true, "s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// BuildContext returns the context selecting source files, for GOOS/GOARCH from environment and tags from flags.
+func (d *Derive) BuildContext() *build.Context {
+	ctx := build.Default
+	ctx.BuildTags = d.Tags
+	return &ctx
+}
+
+// fileConstraint returns the build constraint of the file from its //go:build (or // +build) lines and
+// _GOOS_GOARCH name suffix, or an empty string if the file is unconstrained.
+func fileConstraint(filename string, file *ast.File) string {
+	var expr constraint.Expr
+	and := func(x constraint.Expr) {
+		if expr == nil {
+			expr = x
+		} else {
+			expr = &constraint.AndExpr{X: expr, Y: x}
+		}
+	}
+
+	var goBuild constraint.Expr
+	var plusBuilds []constraint.Expr
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, cmt := range group.List {
+			if !constraint.IsGoBuild(cmt.Text) && !constraint.IsPlusBuild(cmt.Text) {
+				continue
+			}
+			x, err := constraint.Parse(cmt.Text)
+			if err != nil {
+				continue
+			}
+			if constraint.IsGoBuild(cmt.Text) {
+				goBuild = x
+			} else {
+				plusBuilds = append(plusBuilds, x)
+			}
+		}
+	}
+	// //go:build line takes precedence over // +build lines
+	if goBuild != nil {
+		and(goBuild)
+	} else {
+		for _, x := range plusBuilds {
+			and(x)
+		}
+	}
+
+	name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(filename), ".go"), "_test")
+	if idx := strings.Index(name, "_"); idx >= 0 {
+		parts := strings.Split(name[idx:], "_")
+		n := len(parts)
+		if n >= 2 && knownOS[parts[n-2]] && knownArch[parts[n-1]] {
+			and(&constraint.AndExpr{X: &constraint.TagExpr{Tag: parts[n-2]}, Y: &constraint.TagExpr{Tag: parts[n-1]}})
+		} else if knownOS[parts[n-1]] || knownArch[parts[n-1]] {
+			and(&constraint.TagExpr{Tag: parts[n-1]})
+		}
+	}
+
+	if expr == nil {
+		return ""
+	}
+	return expr.String()
+}
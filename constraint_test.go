@@ -0,0 +1,27 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFileConstraint(t *testing.T) {
+	Convey("file constraint", t, func() {
+		constraintOf := func(filename, src string) string {
+			file, err := parser.ParseFile(token.NewFileSet(), filename, src, parser.ParseComments)
+			So(err, ShouldBeNil)
+			return fileConstraint(filename, file)
+		}
+		So(constraintOf("foo.go", "package foo\n"), ShouldBeEmpty)
+		So(constraintOf("foo_linux.go", "package foo\n"), ShouldEqual, "linux")
+		So(constraintOf("foo_linux_amd64_test.go", "package foo\n"), ShouldEqual, "linux && amd64")
+		So(constraintOf("linux.go", "package foo\n"), ShouldBeEmpty)
+		So(constraintOf("foo.go", "//go:build !cgo || race\n\npackage foo\n"), ShouldEqual, "!cgo || race")
+		So(constraintOf("foo.go", "// +build linux darwin\n// +build !cgo\n\npackage foo\n"), ShouldEqual, "(linux || darwin) && !cgo")
+		So(constraintOf("foo_arm64.go", "//go:build a || b\n\npackage foo\n"), ShouldEqual, "(a || b) && arm64")
+		So(constraintOf("foo.go", "package foo\n\n//go:build ignore\n"), ShouldBeEmpty)
+	})
+}
@@ -4,11 +4,19 @@ import (
 	"bytes"
 	"fmt"
 	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"runtime/debug"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/nextzhou/goderive/plugin"
 	"github.com/nextzhou/goderive/plugin/access"
@@ -44,52 +52,195 @@ func main() {
 }
 
 type Derive struct {
-	Plugins     *plugin.PluginSet
-	Cmd         *cobra.Command
-	Err         error
-	Output      string
-	Delete      bool
-	ExcludeDirs []string
-	ExcludeExts []string
-	ShowVersion bool
+	// registered plugins, read-only while running
+	Plugins       *plugin.PluginSet
+	Cmd           *cobra.Command
+	Err           error
+	Output        string
+	OutputMode    string
+	OutputPattern string
+	Delete        bool
+	ExcludeDirs   []string
+	ExcludeExts   []string
+	Includes      []string
+	Excludes      []string
+	GitIgnore     bool
+	Opts          []string
+	OptsOverride  bool
+	// plugins enabled, all the registered ones if empty
+	EnabledPlugins     []string
+	SkipPlugins        []string
+	WarnUnknownPlugins bool
+	Manifest           string
+	Check              bool
+	Diff               bool
+	KeepGoing          bool
+	Force              bool
+	Jobs               int
+	Tags               []string
+	NoCache            bool
+	CacheDir           string
+	Watch              bool
+	Stdin              bool
+	Stdout             bool
+	StdinFilename      string
+	WatchInterval      time.Duration
+	Format             string
+	ShowVersion        bool
 
-	excludeDirs *utils.StrSet
-	excludeExts *utils.StrSet
+	reporter Reporter
+	cache    *Cache
+	// absolute paths of directories scanned by ListGoFiles
+	scannedDirs *utils.StrSet
+	// settings of directories by absolute path
+	settings   map[string]*Settings
+	settingsMu sync.Mutex
+	// main modules, loaded when resolving import paths
+	modules []Module
+	// only files of the package are processed if not empty, e.g. invoked by go:generate
+	onlyPackage string
+	// plugin options parsed from Opts
+	cmdPluginOptions map[string]*plugin.Options
+	// warnings found while extracting types, reported with errors of the run
+	warnings   utils.ErrorList
+	warningsMu sync.Mutex
+	// plugins skipped in command line by absolute path of the output they would be generated into
+	skippedOutputs   map[string][]skippedPlugin
+	skippedOutputsMu sync.Mutex
 }
 
 func NewDerive() *Derive {
 	derive := new(Derive)
 	derive.Plugins = plugin.NewPluginSet(0)
 	derive.Cmd = &cobra.Command{
-		Use: "goderive",
+		Use:  "goderive",
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if derive.ShowVersion {
 				fmt.Printf("Version: %s\n", Version)
 				return nil
 			}
-			if len(args) > 0 && args[0] == "help" {
-				return derive.Help(args[1:])
+			if derive.Diff {
+				derive.Check = true
+			}
+			if err := validateOutputMode(derive.OutputMode); err != nil {
+				return err
+			}
+			if err := validateOutputPattern(derive.OutputPattern); err != nil {
+				return err
+			}
+			if err := validateGlobs(append(derive.Includes, derive.Excludes...)); err != nil {
+				return err
+			}
+			for _, pluginID := range append(append([]string(nil), derive.EnabledPlugins...), derive.SkipPlugins...) {
+				if _, err := derive.GetPlugin(pluginID); err != nil {
+					return err
+				}
+			}
+			pluginOptions, err := derive.parseOpts(derive.Opts)
+			if err != nil {
+				return err
+			}
+			derive.cmdPluginOptions = pluginOptions
+			if len(args) == 1 && args[0] == "-" {
+				derive.Stdin, derive.Stdout = true, true
+				args = nil
+			}
+			if derive.Stdin && (!derive.Stdout || len(args) > 0) {
+				return fmt.Errorf("--stdin requires --stdout and no path")
+			}
+			if derive.Stdout && (derive.Check || derive.Watch) {
+				return fmt.Errorf("--stdout can't be used with --check, --diff or --watch")
+			}
+			// invoked by go:generate without path, process only the invoking package in current directory
+			if len(args) == 0 && os.Getenv("GOFILE") != "" {
+				derive.onlyPackage = os.Getenv("GOPACKAGE")
+			}
+			// generated code takes stdout
+			reportWriter := io.Writer(os.Stdout)
+			if derive.Stdout {
+				reportWriter = os.Stderr
+			}
+			reporter, err := NewReporter(derive.Format, reportWriter, os.Stderr)
+			if err != nil {
+				return err
+			}
+			derive.reporter = reporter
+			if derive.Manifest != "" && (derive.Stdin || derive.Stdout || derive.Watch || derive.Check) {
+				return fmt.Errorf("--manifest can't be used with --stdin, --stdout, --watch, --check or --diff")
+			}
+			// manifests are recorded while generating, and warnings of skipped plugins while parsing,
+			// cached packages are neither generated nor parsed
+			if !derive.NoCache && derive.Manifest == "" && !derive.filtersPlugins() {
+				if derive.CacheDir == "" {
+					derive.CacheDir = DefaultCacheDir()
+				}
+				if derive.CacheDir != "" {
+					derive.cache = &Cache{Dir: derive.CacheDir}
+				}
+			}
+			if derive.Stdin {
+				return derive.RunStdin(os.Stdin, os.Stdout)
+			}
+			if derive.Watch {
+				return derive.RunWatch(args)
 			}
-			derive.excludeDirs = utils.NewStrSetFromSlice(derive.ExcludeDirs)
-			derive.excludeExts = utils.NewStrSetFromSlice(derive.ExcludeExts)
 			return derive.Run(args)
 		},
 		SilenceUsage: true,
 	}
+	derive.Cmd.SetHelpCommand(&cobra.Command{
+		Use:   "help [plugin ...]",
+		Short: "Show help of goderive or plugins",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return derive.Help(args)
+		},
+		SilenceUsage: true,
+	})
+	derive.Cmd.AddCommand(newCacheCommand())
+	derive.Cmd.AddCommand(derive.newListCommand())
+	derive.Cmd.AddCommand(derive.newCleanCommand())
 	derive.Cmd.Flags().StringVarP(&derive.Output, "output", "o", "derived.gen.go", "output file name")
+	derive.Cmd.Flags().StringVar(&derive.OutputMode, "output-mode", OutputModePackage, "package: an output file for each package, per-file: an output file for each source file")
+	derive.Cmd.Flags().StringVar(&derive.OutputPattern, "output-pattern", "{name}_derived.gen.go", "output file name in per-file mode, where {name} is the source file name without .go")
 	derive.Cmd.Flags().BoolVarP(&derive.Delete, "delete", "d", true, "delete existing generated file when no derived type")
 	derive.Cmd.Flags().StringSliceVarP(&derive.ExcludeDirs, "exclude-dir", "D", []string{"vendor"}, "exclude the given comma separated directories")
-	derive.Cmd.Flags().StringSliceVarP(&derive.ExcludeExts, "exclude-ext", "E", []string{".gen.go", "_test.go"}, "exclude the files having given file name ext")
+	derive.Cmd.Flags().StringSliceVarP(&derive.ExcludeExts, "exclude-ext", "E", []string{".gen.go", ".gen_test.go"}, "exclude the files having given file name ext")
+	derive.Cmd.Flags().StringArrayVar(&derive.Includes, "include", nil, "scan only files matching the doublestar glob relative to the module root, or in matching directories (repeatable)")
+	derive.Cmd.Flags().StringArrayVar(&derive.Excludes, "exclude", nil, "skip files and directories matching the doublestar glob relative to the module root (repeatable)")
+	derive.Cmd.Flags().StringArrayVar(&derive.Opts, "opt", nil, "options of all types derived by the plugin, as plugin.Key=Value, plugin.Flag or plugin.!Flag (repeatable)")
+	derive.Cmd.Flags().BoolVar(&derive.OptsOverride, "opt-override", false, "options of --opt take precedence over annotations, instead of the other way around")
+	derive.Cmd.Flags().StringSliceVar(&derive.EnabledPlugins, "plugins", nil, "comma separated plugins to generate code with, all plugins if empty")
+	derive.Cmd.Flags().StringSliceVar(&derive.SkipPlugins, "skip-plugins", nil, "comma separated plugins not to generate code with, their annotations are skipped with warnings")
+	derive.Cmd.Flags().BoolVar(&derive.WarnUnknownPlugins, "warn-unknown-plugins", false, "skip annotations of unknown plugins with warnings instead of errors")
+	derive.Cmd.Flags().StringVar(&derive.Manifest, "manifest", "", "write generated outputs, types, plugins, options and identifiers emitted by each plugin to the JSON file, which disables the cache")
+	derive.Cmd.Flags().BoolVar(&derive.GitIgnore, "gitignore", false, "skip files and directories ignored by .gitignore files in the module")
+	derive.Cmd.Flags().BoolVar(&derive.Check, "check", false, "verify generated files are up to date without writing them")
+	derive.Cmd.Flags().BoolVar(&derive.Diff, "diff", false, "like --check, and print a unified diff for each stale file")
+	derive.Cmd.Flags().BoolVarP(&derive.KeepGoing, "keep-going", "k", false, "write generated files of valid packages even if other packages fail")
+	derive.Cmd.Flags().BoolVarP(&derive.Force, "force", "f", false, "overwrite output files even if they are not generated by goderive, which are never deleted")
+	derive.Cmd.Flags().IntVarP(&derive.Jobs, "jobs", "j", 0, "number of files parsed and packages generated in parallel, GOMAXPROCS if not positive")
+	derive.Cmd.Flags().StringSliceVar(&derive.Tags, "tags", nil, "comma separated build tags to select source files, with GOOS and GOARCH from environment")
+	derive.Cmd.Flags().BoolVar(&derive.NoCache, "no-cache", false, "regenerate all packages without reading or updating the cache")
+	derive.Cmd.Flags().StringVar(&derive.CacheDir, "cache-dir", "", "cache directory, $XDG_CACHE_HOME/goderive by default")
+	derive.Cmd.Flags().BoolVar(&derive.Stdin, "stdin", false, "read a single source file from stdin instead of paths, requires --stdout")
+	derive.Cmd.Flags().BoolVar(&derive.Stdout, "stdout", false, "write generated code to stdout instead of files, which must be a single file")
+	derive.Cmd.Flags().StringVar(&derive.StdinFilename, "stdin-filename", "<stdin>", "file name of the source read from stdin, used in positions and file name based build constraints")
+	derive.Cmd.Flags().BoolVarP(&derive.Watch, "watch", "w", false, "keep running, and regenerate packages when their go files change")
+	derive.Cmd.Flags().DurationVar(&derive.WatchInterval, "watch-interval", time.Second, "interval of polling file changes in watch mode")
+	derive.Cmd.Flags().StringVar(&derive.Format, "format", FormatText, "output format of diagnostics and results: text or json")
 	derive.Cmd.Flags().BoolVarP(&derive.ShowVersion, "version", "v", false, "show version information")
 	return derive
 }
 
+// RegisterPlugin registers plugins, it must be called before Execute.
 func (d *Derive) RegisterPlugin(plugins ...plugin.Plugin) {
 	d.Plugins.Append(plugins...)
 }
 
 func (d *Derive) Execute() error {
-	// set help template after plugin registration
+	// set help template after plugin registration, and with the help flag listed
+	d.Cmd.InitDefaultHelpFlag()
 	d.Cmd.SetHelpTemplate(d.HelpString())
 	if d.Err != nil {
 		return d.Err
@@ -97,7 +248,7 @@ func (d *Derive) Execute() error {
 	return d.Cmd.Execute()
 }
 
-func (d Derive) HelpString() string {
+func (d *Derive) HelpString() string {
 	help := bytes.NewBufferString(`GoDerive
 
 Add derive comment above your type, and generate source code for the marked type.
@@ -108,19 +259,17 @@ Comment Format:
   type YourType struct{/* ... */}
 
 Usage:
-  goderive [flags] [path ...] # where a '/...' suffix includes all sub-directories
+  goderive [flags] [path ...] # where a '/...' suffix includes all sub-directories, path can be an import path
+  goderive [flags] - # read a source file from stdin, and write generated code to stdout
   goderive help [plugin ...]
+  goderive cache clean # remove all cached packages
+  goderive list [--format table|json] [path ...] # list annotated types with their plugins and resolved options
+  goderive clean [--dry-run] [path ...] # remove files generated by goderive
 
 Flags:
-  -d, --delete                delete existing generated file when no derived type (default true)
-  -D, --exclude-dir strings   exclude the given comma separated directories (default [vendor])
-  -E, --exclude-ext strings   exclude the files having given file name ext (default [.gen.go,_test.go])
-  -h, --help                  help for goderive
-  -o, --output string         output file name (default "derived.gen.go")
-  -v, --version               show version information
-
-Plugins:
 `)
+	help.WriteString(d.Cmd.Flags().FlagUsages())
+	help.WriteString("\nPlugins:\n")
 	w := utils.NewTableWriter(help)
 	d.Plugins.ForEach(func(plg plugin.Plugin) {
 		desc := plg.Describe()
@@ -136,21 +285,39 @@ func (d *Derive) ListGoFiles(path string, recursive bool) ([]string, error) {
 		path = strings.TrimSuffix(path, "/...")
 	}
 	stat, err := os.Stat(path)
+	if os.IsNotExist(err) && isImportPath(path) {
+		if path, err = d.ResolveImportPath(path); err != nil {
+			return nil, err
+		}
+		stat, err = os.Stat(path)
+	}
 	if err != nil {
 		return nil, err
 	}
 	var files []string
 	if stat.IsDir() {
+		settings, err := d.Settings(path)
+		if err != nil {
+			return nil, err
+		}
+		// with include patterns, only directories having included files are in scope,
+		// so outputs of the others are never deleted as stale
+		inScope := len(settings.Includes) == 0
+		ctx := d.BuildContext()
 		dirInfo, err := ioutil.ReadDir(path)
 		if err != nil {
 			return nil, err
 		}
 		for _, entry := range dirInfo {
-			if d.ExcludePath(entry.Name(), entry.IsDir()) {
+			if settings.Skip(filepath.Join(path, entry.Name()), entry.IsDir()) {
+				continue
+			}
+			// outputs are never inputs, while hand-written files named as outputs are
+			if !entry.IsDir() && isGeneratedOutput(settings, filepath.Join(path, entry.Name())) {
 				continue
 			}
 			if entry.IsDir() {
-				if recursive {
+				if recursive && !skipSubDir(path, entry.Name()) {
 					subDirFiles, err := d.ListGoFiles(filepath.Join(path, entry.Name()), recursive)
 					if err != nil {
 						return nil, err
@@ -158,9 +325,17 @@ func (d *Derive) ListGoFiles(path string, recursive bool) ([]string, error) {
 					files = append(files, subDirFiles...)
 				}
 			} else if strings.HasSuffix(entry.Name(), ".go") {
+				inScope = true
+				// files are parsed if their build constraints can't be evaluated, to report errors
+				if match, err := ctx.MatchFile(path, entry.Name()); err == nil && !match {
+					continue
+				}
 				files = append(files, filepath.Join(path, entry.Name()))
 			}
 		}
+		if absPath, err := filepath.Abs(path); err == nil && inScope && d.scannedDirs != nil {
+			d.scannedDirs.Append(absPath)
+		}
 	} else {
 		if strings.HasSuffix(path, ".go") {
 			files = []string{path}
@@ -171,146 +346,754 @@ func (d *Derive) ListGoFiles(path string, recursive bool) ([]string, error) {
 	return files, nil
 }
 
-func (d *Derive) ExcludePath(name string, isDir bool) bool {
-	// skip ".", ".." and hidden file/dir
-	if name[0] == '.' {
-		return true
-	}
-	if isDir {
-		return d.excludeDirs.Contains(name)
-	} else {
-		return d.excludeExts.Any(func(ext string) bool { return strings.HasSuffix(name, ext) })
-	}
-}
-
 func (d *Derive) Run(inputPaths []string) error {
+	// all errors are collected and reported at last
+	var errs utils.ErrorList
+
 	// scan go source file
 	if len(inputPaths) == 0 {
 		inputPaths = []string{"."}
 	}
 	files := utils.NewStrSet(0)
+	d.scannedDirs = utils.NewStrSet(0)
+	d.skippedOutputs = make(map[string][]skippedPlugin)
 	for _, path := range inputPaths {
 		fs, err := d.ListGoFiles(path, false)
 		if err != nil {
-			return err
+			errs.Add(err)
+			continue
 		}
 		files.Append(fs...)
 	}
 
-	// extract type info, and group them by package(path)
-	groupTypesByPath := make(map[string][]TypeInfo)
-	err := files.DoUntilError(func(file string) error {
+	if d.onlyPackage != "" {
+		files = files.Filter(func(file string) bool {
+			pkgName := packageClause(file)
+			return pkgName == "" || pkgName == d.onlyPackage
+		})
+	}
+
+	// group files by package(path), unchanged packages are taken from cache without parsing
+	var filePaths []string
+	filesByPath := make(map[string][]string)
+	files.ForEach(func(file string) {
 		path, err := filepath.Abs(filepath.Dir(file))
 		if err != nil {
 			panic(err)
 		}
-		pkgTypes := groupTypesByPath[path]
+		if _, ok := filesByPath[path]; !ok {
+			filePaths = append(filePaths, path)
+		}
+		filesByPath[path] = append(filesByPath[path], file)
+	})
+	cacheKeys := make(map[string]string)
+	cachedOutputs := make(map[string][]GeneratedFile)
+	if d.cache != nil {
+		salt := d.cacheSalt()
+		keys := make([]string, len(filePaths))
+		outputs := make([][]GeneratedFile, len(filePaths))
+		hits := make([]bool, len(filePaths))
+		utils.ParallelDo(d.workers(), len(filePaths), func(idx int) {
+			// configured default options affect generated code as well, and output settings affect file names
+			settings := d.settingsOf(filePaths[idx])
+			inputs := append(append([]string(nil), filesByPath[filePaths[idx]]...), settings.ConfigFiles...)
+			outputSalt := fmt.Sprintf("%s %s %s\n", settings.Output, settings.OutputMode, settings.OutputPattern)
+			key, err := d.packageCacheKey(append([]byte(outputSalt), salt...), inputs)
+			if err != nil {
+				// let parsing report the error
+				return
+			}
+			keys[idx] = key
+			outputs[idx], hits[idx] = d.getCachedOutputs(filePaths[idx], key)
+		})
+		for idx, path := range filePaths {
+			if keys[idx] == "" {
+				continue
+			}
+			cacheKeys[path] = keys[idx]
+			if hits[idx] {
+				cachedOutputs[path] = outputs[idx]
+			}
+		}
+	}
 
-		src, err := ioutil.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("read %#v : %s", file, err.Error())
+	// extract type info concurrently, and group them by package(path) in the order of files
+	var fileList []string
+	for _, path := range filePaths {
+		if _, ok := cachedOutputs[path]; !ok {
+			fileList = append(fileList, filesByPath[path]...)
 		}
-		fileTypes, err := ExtractTypes(src)
+	}
+	fileTypes := make([][]TypeInfo, len(fileList))
+	fileErrs := make([]error, len(fileList))
+	utils.ParallelDo(d.workers(), len(fileList), func(idx int) {
+		fileTypes[idx], fileErrs[idx] = d.ExtractFileTypes(fileList[idx])
+	})
+	groupTypesByPath := make(map[string][]TypeInfo)
+	// packages with any invalid file or type won't be generated
+	failedPaths := utils.NewStrSet(0)
+	for idx, file := range fileList {
+		path, err := filepath.Abs(filepath.Dir(file))
 		if err != nil {
-			return fmt.Errorf("%s: %s", file, err.Error())
+			panic(err)
 		}
-		if len(fileTypes) == 0 {
-			return nil
+		if fileErrs[idx] != nil {
+			errs.Add(fileErrs[idx])
+			failedPaths.Append(path)
 		}
-		for _, typ := range fileTypes {
-			err := typ.Plugins.DoUntilError(func(plg plugin.Entry) error {
-				if err := d.ValidatePluginOptions(plg.Plugin, plg.Opts); err != nil {
-					return fmt.Errorf("%#v: type %s: %v", file, typ.Name, err)
-				}
-				return nil
-			})
-			if err != nil {
-				return err
+		if len(fileTypes[idx]) == 0 {
+			continue
+		}
+		groupTypesByPath[path] = append(groupTypesByPath[path], fileTypes[idx]...)
+	}
+	for path, files := range cachedOutputs {
+		if len(files) > 0 {
+			groupTypesByPath[path] = nil
+		}
+	}
+
+	// scanned packages having no derived type any more
+	d.scannedDirs.ForEach(func(path string) {
+		if _, ok := groupTypesByPath[path]; !ok && d.settingsOf(path).Delete {
+			groupTypesByPath[path] = nil
+		}
+	})
+
+	// generate all packages in memory concurrently, results are collected in the order of path
+	paths := make([]string, 0, len(groupTypesByPath))
+	for path := range groupTypesByPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	pkgOutputs := make([][]GeneratedFile, len(paths))
+	pkgErrs := make([]error, len(paths))
+	utils.ParallelDo(d.workers(), len(paths), func(idx int) {
+		path := paths[idx]
+		if failedPaths.Contains(path) {
+			return
+		}
+		files, ok := cachedOutputs[path]
+		if !ok {
+			if files, pkgErrs[idx] = d.GenerateOutput(path, groupTypesByPath[path]); pkgErrs[idx] != nil {
+				return
 			}
 		}
-		pkgTypes = append(pkgTypes, fileTypes...)
-		groupTypesByPath[path] = pkgTypes
-		return nil
+		pkgOutputs[idx] = d.withStaleOutputs(path, files)
 	})
+	var outputs []GeneratedFile
+	generatedOutputs := make(map[string][]GeneratedFile)
+	for idx, path := range paths {
+		if pkgErrs[idx] != nil {
+			errs.Add(pkgErrs[idx])
+			failedPaths.Append(path)
+			continue
+		}
+		outputs = append(outputs, pkgOutputs[idx]...)
+		generatedOutputs[path] = pkgOutputs[idx]
+	}
+
+	// cache packages generated successfully, including the ones without derived type.
+	// check runs only read the cache.
+	for _, path := range filePaths {
+		key, ok := cacheKeys[path]
+		if _, cached := cachedOutputs[path]; d.Check || !ok || cached || failedPaths.Contains(path) {
+			continue
+		}
+		if err := d.putCachedOutputs(key, generatedOutputs[path]); err != nil {
+			d.reporter.Diagnostic(fmt.Errorf("failed to update cache: %v", err), utils.SeverityWarning)
+		}
+	}
+
+	var results []PackageResult
+	var changedFiles []GeneratedFile
+	for _, file := range outputs {
+		// existing files don't matter to stdout
+		if d.Stdout {
+			break
+		}
+		result, changed, err := d.compareFile(file)
+		if err != nil {
+			errs.Add(err)
+			failedPaths.Append(file.Path)
+			continue
+		}
+		if changed {
+			changedFiles = append(changedFiles, file)
+		}
+		if result.Status != "" {
+			results = append(results, result)
+		}
+	}
+
+	// keep the broken code for debugging, away from the real output paths, so it's written even if nothing else is
+	for _, err := range errs {
+		if invalidErr, ok := err.(*utils.InvalidCodeError); ok && invalidErr.Output != "" && !d.Check && !d.Stdout {
+			debugFile := invalidErr.Output + invalidSuffix
+			if ioutil.WriteFile(debugFile, invalidErr.Src, 0644) == nil {
+				invalidErr.DebugFile = displayPath(debugFile)
+			}
+		}
+	}
+	// nothing will be written if any package fails, unless keep going
+	if !d.Check && !d.Stdout && (len(errs) == 0 || d.KeepGoing) {
+		// broken code of previous runs is stale once the output is generated
+		for _, file := range outputs {
+			debugFile := file.Filename + invalidSuffix
+			if src, err := ioutil.ReadFile(debugFile); err == nil && utils.IsGeneratedFile(src) {
+				changedFiles = append(changedFiles, GeneratedFile{Path: file.Path, Filename: debugFile})
+			}
+		}
+	}
+
+	d.reportWarnings()
+	errs.Sort()
+	for _, err := range errs {
+		d.reporter.Diagnostic(err, utils.SeverityError)
+	}
+	failed := failedPaths.ToSlice()
+	sort.Strings(failed)
+	for _, path := range failed {
+		d.reporter.Package(PackageResult{Path: displayPath(path), Output: displayPath(d.outputFile(path)), Status: StatusFailed})
+	}
+	var summary error
+	if len(errs) > 0 {
+		summary = fmt.Errorf("%d error(s) found", len(errs))
+	}
+
+	if d.Check {
+		for _, result := range results {
+			d.reporter.Package(result)
+		}
+		if len(changedFiles) > 0 {
+			return fmt.Errorf("%d generated file(s) out of date", len(changedFiles))
+		}
+		return summary
+	}
+
+	// nothing will be written if any package fails, unless keep going
+	if summary != nil && !d.KeepGoing {
+		return summary
+	}
+	if d.Stdout {
+		// stdout holds exactly the content of a file, so it can be redirected to the output
+		var generated []string
+		var src []byte
+		for _, file := range outputs {
+			if file.Src != nil {
+				generated = append(generated, displayPath(file.Filename))
+				src = file.Src
+			}
+		}
+		if len(generated) > 1 {
+			return fmt.Errorf("--stdout writes a single file, but %d files would be generated: %s",
+				len(generated), strings.Join(generated, ", "))
+		}
+		os.Stdout.Write(src)
+		return summary
+	}
+	if err := d.WriteFiles(changedFiles); err != nil {
+		return err
+	}
+	for _, result := range results {
+		d.reporter.Package(result)
+	}
+	// the manifest describes outputs written, or left unchanged, by the run
+	if d.Manifest != "" {
+		if err := WriteManifest(d.Manifest, outputs); err != nil {
+			return fmt.Errorf("write manifest: %s", err.Error())
+		}
+	}
+	return summary
+}
+
+// RunStdin generates code for types in the source read from r, and writes it to w. No file is touched.
+func (d *Derive) RunStdin(r io.Reader, w io.Writer) error {
+	src, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
 	}
+	var errs utils.ErrorList
+	types, err := d.ExtractSourceTypes(d.StdinFilename, src)
+	errs.Add(err)
+	if err == nil && len(types) > 0 {
+		files, err := d.GenerateOutput(filepath.Dir(d.StdinFilename), types)
+		errs.Add(err)
+		for _, file := range files {
+			w.Write(file.Src)
+		}
+	}
+	d.reportWarnings()
+	errs.Sort()
+	for _, err := range errs {
+		d.reporter.Diagnostic(err, utils.SeverityError)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d error(s) found", len(errs))
+	}
+	return nil
+}
+
+// packageClause returns the package name of the go file, or an empty string if it can't be parsed.
+func packageClause(filename string) string {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return ""
+	}
+	return file.Name.Name
+}
 
-	var shouldDeletedFiles []string
-	for path, types := range groupTypesByPath {
-		filename := filepath.Join(path, d.Output)
-		if len(types) == 0 {
-			if d.Delete {
-				shouldDeletedFiles = append(shouldDeletedFiles, filename)
+// compareFile compares the expected file with the existing one, reports whether it should be written or deleted.
+// Result status is empty if there is nothing to report.
+// Files not generated by goderive are refused to be overwritten unless forced, and never deleted.
+// Files holding code of disabled plugins are refused to be changed, and skipped by checks.
+func (d *Derive) compareFile(file GeneratedFile) (PackageResult, bool, error) {
+	result := PackageResult{Path: displayPath(file.Path), Output: displayPath(file.Filename)}
+	existing, err := ioutil.ReadFile(file.Filename)
+	if err != nil && !os.IsNotExist(err) {
+		return result, false, fmt.Errorf("read %#v : %s", file.Filename, err.Error())
+	}
+	exists := err == nil
+	if file.Src == nil && !exists {
+		return result, false, nil
+	}
+	if exists && !utils.IsGeneratedFile(existing) {
+		// a stale candidate named as an output may be written by hand
+		if file.Src == nil {
+			return result, false, nil
+		}
+		if !d.Force {
+			return result, false, &utils.NotGeneratedFileError{Filename: result.Output, Action: "overwrite"}
+		}
+	}
+	if exists && file.Src != nil && bytes.Equal(existing, file.Src) {
+		result.Status = StatusUnchanged
+		return result, false, nil
+	}
+	// code of disabled plugins in the existing file can't be generated again, nor checked
+	if plugins := d.heldSkippedPlugins(file.Filename, existing); len(plugins) > 0 {
+		if d.Check {
+			result.Status = StatusSkipped
+			return result, false, nil
+		}
+		action := "overwrite"
+		if file.Src == nil {
+			action = "delete"
+		}
+		return result, false, &utils.SkippedPluginOutputError{Filename: result.Output, Action: action, Plugins: plugins}
+	}
+	switch {
+	case d.Check:
+		result.Status = StatusStale
+		if d.Diff {
+			result.Diff = utils.UnifiedDiff("a/"+result.Output, "b/"+result.Output, existing, file.Src)
+		}
+	case file.Src == nil:
+		result.Status = StatusDeleted
+	default:
+		result.Status = StatusGenerated
+	}
+	return result, true, nil
+}
+
+// ExtractFileTypes extracts derived types from file, and validates their plugin options.
+// Valid types are returned even if there are errors.
+func (d *Derive) ExtractFileTypes(file string) ([]TypeInfo, error) {
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read %#v : %s", file, err.Error())
+	}
+	return d.ExtractSourceTypes(file, src)
+}
+
+// ExtractSourceTypes extracts types from the source of file, and validates their options.
+func (d *Derive) ExtractSourceTypes(file string, src []byte) ([]TypeInfo, error) {
+	settings, err := d.Settings(filepath.Dir(file))
+	if err != nil {
+		return nil, err
+	}
+	var errs utils.ErrorList
+	fileTypes, err := ExtractTypes(file, src)
+	errs.Add(err)
+	validTypes := make([]TypeInfo, 0, len(fileTypes))
+	for _, typ := range fileTypes {
+		typ.Plugins = typ.Plugins.Filter(func(plg plugin.Entry) bool {
+			if _, err := d.GetPlugin(plg.Plugin); err != nil && d.WarnUnknownPlugins {
+				d.warn(utils.Positioned(err, plg.Pos, typ.Name, plg.Plugin))
+				return false
+			}
+			if !d.pluginEnabled(plg.Plugin) {
+				d.warn(utils.Positioned(&utils.SkippedPluginError{Plugin: plg.Plugin}, plg.Pos, typ.Name, plg.Plugin))
+				d.skipOutput(file, settings, typ, plg)
+				return false
 			}
+			return true
+		})
+		// types with disabled plugins only are left alone
+		if typ.Plugins.IsEmpty() {
 			continue
 		}
-		headBuf := bytes.NewBuffer(nil)
-		headBuf.WriteString(utils.HeaderComment)
-		headBuf.WriteString(fmt.Sprintf("package %s\n\n", types[0].Env.PkgName))
-		imports := plugin.NewImportSet(0, func(i, j plugin.Import) bool { return i.String() < j.String() })
-		bodyBuf := bytes.NewBuffer(nil)
-		for _, typ := range types {
-			err := typ.Plugins.DoUntilError(func(plg plugin.Entry) error {
-				p, _ := d.GetPlugin(plg.Plugin)
-				typeInfo := plugin.TypeInfo{Name: typ.Name, Ast: typ.Ast, Assigned: typ.Assigned}
-				prerequisites, err := p.GenerateTo(bodyBuf, typ.Env, typeInfo, *plg.Opts)
-				if err != nil {
-					// TODO log file path of type
-					return fmt.Errorf("failed to generate code of type %s: %v", typ.Name, err)
-				}
-				imports.InPlaceUnion(prerequisites.Imports)
-				return nil
-			})
-			if err != nil {
-				return err
+		valid := true
+		typ.Plugins.ForEach(func(plg plugin.Entry) {
+			d.resolveOptions(settings, plg)
+			if err := d.ValidatePluginOptions(plg.Plugin, plg.Opts); err != nil {
+				errs.Add(utils.Positioned(err, plg.Pos, typ.Name, plg.Plugin))
+				valid = false
 			}
+		})
+		if valid {
+			validTypes = append(validTypes, typ)
 		}
-		// TODO write file after all generating
+	}
+	return validTypes, errs.Err()
+}
 
-		switch imports.Len() {
-		case 0:
-		case 1:
-			headBuf.WriteString(fmt.Sprintf("import %s\n", imports.ToSliceRef()[0]))
-		default:
-			headBuf.WriteString("import (\n")
-			localPkgs, remotePkgs := imports.GroupByBool(func(i plugin.Import) bool {
-				return utils.IsLocalPath(i.Path)
-			})
-			writeImport := func(i plugin.Import) {
-				if i.Path == "" {
-					return
-				}
-				headBuf.WriteString(fmt.Sprintf("\t%s\n", i))
+// resolveOptions merges options from command line and configured default options into the annotated ones.
+func (d *Derive) resolveOptions(settings *Settings, plg plugin.Entry) {
+	// annotations take precedence over options from command line unless overridden,
+	// and both of them take precedence over configured default options
+	if d.OptsOverride {
+		plg.Opts.Override(d.cmdPluginOptions[plg.Plugin])
+	} else {
+		plg.Opts.MergeDefault(d.cmdPluginOptions[plg.Plugin])
+	}
+	plg.Opts.MergeDefault(settings.PluginOptions[plg.Plugin])
+}
+
+// WriteFiles stages all files as temporary files first, then renames them to their destinations.
+// Existing files are left untouched if any file fails to be staged. Files without source are deleted at last.
+func (d *Derive) WriteFiles(files []GeneratedFile) error {
+	var writtenFiles, deletedFiles []string
+	var tmpNames []string
+	for _, file := range files {
+		if file.Src == nil {
+			deletedFiles = append(deletedFiles, file.Filename)
+			continue
+		}
+		tmpName, err := utils.WriteTempFile(file.Filename, file.Src, 0644)
+		if err != nil {
+			for _, name := range tmpNames {
+				os.Remove(name)
 			}
-			localPkgs.ForEach(writeImport)
-			if !(localPkgs.IsEmpty() || remotePkgs.IsEmpty()) {
-				headBuf.WriteByte('\n')
+			return fmt.Errorf("write %#v error : %s", file.Filename, err.Error())
+		}
+		writtenFiles = append(writtenFiles, file.Filename)
+		tmpNames = append(tmpNames, tmpName)
+	}
+	for idx, filename := range writtenFiles {
+		if err := os.Rename(tmpNames[idx], filename); err != nil {
+			for _, name := range tmpNames[idx:] {
+				os.Remove(name)
 			}
-			remotePkgs.ForEach(writeImport)
-			headBuf.WriteString(")\n")
+			return fmt.Errorf("write %#v error : %s", filename, err.Error())
+		}
+	}
+	// all stale files are tried, files already gone are fine
+	var errs utils.ErrorList
+	for _, filename := range deletedFiles {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			errs.Add(fmt.Errorf("delete %#v error : %s", filename, err.Error()))
 		}
+	}
+	return errs.Err()
+}
 
-		headBuf.Write(bodyBuf.Bytes())
+// GenerateOutput generates output files of the package in path. Types from files with build constraint
+// are generated into a variant of the output carrying the same constraint, and types from test files
+// into test files.
+func (d *Derive) GenerateOutput(path string, types []TypeInfo) ([]GeneratedFile, error) {
+	settings := d.settingsOf(path)
+	sortTypes(types)
+	// types are grouped by output file, which holds a single package
+	var names []string
+	typesByName := make(map[string][]TypeInfo)
+	var errs utils.ErrorList
+	conflicts := utils.NewStrSet(0)
+	for _, typ := range types {
+		name := settings.OutputName(typ)
+		if sameOutput, ok := typesByName[name]; !ok {
+			names = append(names, name)
+		} else if first := sameOutput[0]; first.Env.PkgName != typ.Env.PkgName {
+			// report once for each conflicting package
+			if !conflicts.Contains(name + " " + typ.Env.PkgName) {
+				conflicts.Append(name + " " + typ.Env.PkgName)
+				errs.Add(utils.Positioned(&utils.ConflictingPackageError{
+					Packages: []string{first.Env.PkgName, typ.Env.PkgName},
+					Files:    []string{filepath.Base(first.File), filepath.Base(typ.File)},
+					Output:   name,
+				}, typ.Pos, typ.Name, ""))
+			}
+			continue
+		}
+		typesByName[name] = append(typesByName[name], typ)
+	}
+	if len(errs) > 0 {
+		return nil, errs.Err()
+	}
+	sort.Strings(names)
 
-		generatedSrc, err := format.Source(headBuf.Bytes())
+	files := make([]GeneratedFile, 0, len(names))
+	for _, name := range names {
+		filename := filepath.Join(path, name)
+		src, manifestTypes, err := d.generatePackage(typesByName[name])
 		if err != nil {
-			ioutil.WriteFile(filename, headBuf.Bytes(), 0644)
-			panic(fmt.Sprintf("%s: invalid generated code: %s", filename, err.Error()))
+			// the broken code is written for debugging along with outputs
+			if invalidErr, ok := err.(*utils.InvalidCodeError); ok {
+				invalidErr.Output = filename
+			}
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Path:          path,
+			Filename:      filename,
+			Src:           src,
+			pkgName:       typesByName[name][0].Env.PkgName,
+			manifestTypes: manifestTypes,
+		})
+	}
+	return files, nil
+}
+
+// withStaleOutputs appends outputs of the package in path which are not generated any more, if deletion is enabled.
+func (d *Derive) withStaleOutputs(path string, files []GeneratedFile) []GeneratedFile {
+	settings := d.settingsOf(path)
+	if !settings.Delete {
+		return files
+	}
+	generated := utils.NewStrSet(0)
+	for _, file := range files {
+		generated.Append(file.Filename)
+	}
+	var candidates []string
+	if settings.OutputMode == OutputModePackage {
+		candidates = append(candidates, filepath.Join(path, settings.Output))
+	}
+	candidates = append(candidates, d.existingOutputs(path, settings)...)
+	for _, filename := range candidates {
+		// outputs of other packages in the directory are left alone
+		if pkgName := packageClause(filename); d.onlyPackage != "" && pkgName != "" && pkgName != d.onlyPackage {
+			continue
+		}
+		if !generated.Contains(filename) {
+			files = append(files, GeneratedFile{Path: path, Filename: filename})
+			generated.Append(filename)
+		}
+	}
+	return files
+}
+
+// existingOutputs returns existing output files in path of either mode, which are generated by goderive
+// for the current target. Outputs for other targets are left alone.
+func (d *Derive) existingOutputs(path string, settings *Settings) []string {
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+	ctx := d.BuildContext()
+	var outputs []string
+	for _, entry := range entries {
+		if entry.IsDir() || !settings.IsOutputName(entry.Name()) {
+			continue
+		}
+		filename := filepath.Join(path, entry.Name())
+		if src, err := ioutil.ReadFile(filename); err != nil || !utils.IsGeneratedFile(src) {
+			continue
+		}
+		if match, err := ctx.MatchFile(path, entry.Name()); err != nil || !match {
+			continue
+		}
+		outputs = append(outputs, filename)
+	}
+	return outputs
+}
+
+// settingsOf returns settings of dir, or the ones from flags if configuration files are invalid.
+// It's for the directories whose settings were loaded successfully before.
+func (d *Derive) settingsOf(dir string) *Settings {
+	settings, err := d.Settings(dir)
+	if err != nil {
+		return d.baseSettings()
+	}
+	return settings
+}
+
+// outputFile returns the output file name of the package in path, or the pattern of names in per-file mode
+func (d *Derive) outputFile(path string) string {
+	settings := d.settingsOf(path)
+	if settings.OutputMode == OutputModePerFile {
+		return filepath.Join(path, settings.OutputPattern)
+	}
+	return filepath.Join(path, settings.Output)
+}
+
+// workers returns the number of concurrent workers
+func (d *Derive) workers() int {
+	if d.Jobs > 0 {
+		return d.Jobs
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// sortTypes sorts types by source file name, then declaration position.
+// Plugins of a type keep the annotation order.
+func sortTypes(types []TypeInfo) {
+	sort.SliceStable(types, func(i, j int) bool {
+		if types[i].File != types[j].File {
+			return types[i].File < types[j].File
 		}
+		return types[i].Pos.Offset < types[j].Pos.Offset
+	})
+}
+
+type GeneratedFile struct {
+	// package path
+	Path     string
+	Filename string
+	// nil if the file should be deleted
+	Src []byte
+	// package name and generated types, recorded only for the manifest
+	pkgName       string
+	manifestTypes []ManifestType
+}
+
+// GeneratePackage generates formatted source code of derived types in the same package.
+func (d *Derive) GeneratePackage(types []TypeInfo) ([]byte, error) {
+	src, _, err := d.generatePackage(types)
+	return src, err
+}
 
-		err = ioutil.WriteFile(filename, generatedSrc, 0644)
+// generatePackage generates formatted source code of derived types in the same package, and the manifest of
+// the types if required.
+func (d *Derive) generatePackage(types []TypeInfo) ([]byte, []ManifestType, error) {
+	headBuf := bytes.NewBuffer(nil)
+	headBuf.WriteString(utils.HeaderComment)
+	if types[0].Constraint != "" {
+		headBuf.WriteString(fmt.Sprintf("//go:build %s\n\n", types[0].Constraint))
+	}
+	headBuf.WriteString(fmt.Sprintf("package %s\n\n", types[0].Env.PkgName))
+	imports := plugin.NewImportSet(0, func(i, j plugin.Import) bool { return i.String() < j.String() })
+	bodyBuf := bytes.NewBuffer(nil)
+	// record which type and plugin generated each part of body, to locate invalid code
+	var segments []generatedSegment
+	var manifestTypes []ManifestType
+	for _, typ := range types {
+		manifestType := ManifestType{Name: typ.Name, Position: typ.Pos.String()}
+		err := typ.Plugins.DoUntilError(func(plg plugin.Entry) error {
+			segments = append(segments, generatedSegment{
+				StartLine: bytes.Count(bodyBuf.Bytes(), []byte{'\n'}),
+				Type:      typ,
+				Plugin:    plg.Plugin,
+			})
+			p, _ := d.GetPlugin(plg.Plugin)
+			typeInfo := plugin.TypeInfo{Name: typ.Name, Ast: typ.Ast, Assigned: typ.Assigned, Fset: typ.Fset}
+			start := bodyBuf.Len()
+			prerequisites, err := p.GenerateTo(bodyBuf, typ.Env, typeInfo, *plg.Opts)
+			if err != nil {
+				return utils.Positioned(err, plg.Pos, typ.Name, plg.Plugin)
+			}
+			imports.InPlaceUnion(prerequisites.Imports)
+			if d.Manifest != "" {
+				manifestType.Plugins = append(manifestType.Plugins, makeManifestPlugin(makeListedPlugin(plg), bodyBuf.Bytes()[start:]))
+			}
+			return nil
+		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "write %#v error : %s\n", filename, err.Error())
-			os.Exit(1)
+			return nil, nil, err
+		}
+		if d.Manifest != "" {
+			manifestTypes = append(manifestTypes, manifestType)
 		}
 	}
 
-	for _, file := range shouldDeletedFiles {
-		// ignore errors
-		os.Remove(file)
+	switch imports.Len() {
+	case 0:
+	case 1:
+		headBuf.WriteString(fmt.Sprintf("import %s\n", imports.ToSliceRef()[0]))
+	default:
+		headBuf.WriteString("import (\n")
+		localPkgs, remotePkgs := imports.GroupByBool(func(i plugin.Import) bool {
+			return utils.IsLocalPath(i.Path)
+		})
+		writeImport := func(i plugin.Import) {
+			if i.Path == "" {
+				return
+			}
+			headBuf.WriteString(fmt.Sprintf("\t%s\n", i))
+		}
+		localPkgs.ForEach(writeImport)
+		if !(localPkgs.IsEmpty() || remotePkgs.IsEmpty()) {
+			headBuf.WriteByte('\n')
+		}
+		remotePkgs.ForEach(writeImport)
+		headBuf.WriteString(")\n")
 	}
-	return nil
+
+	bodyStartLine := bytes.Count(headBuf.Bytes(), []byte{'\n'})
+	headBuf.Write(bodyBuf.Bytes())
+
+	generatedSrc, err := format.Source(headBuf.Bytes())
+	if err != nil {
+		return nil, nil, newInvalidCodeError(headBuf.Bytes(), err, bodyStartLine, segments)
+	}
+	return generatedSrc, manifestTypes, nil
+}
+
+type generatedSegment struct {
+	// 0-based line offset in body
+	StartLine int
+	Type      TypeInfo
+	Plugin    string
+}
+
+const invalidCodeContextLines = 3
+
+func newInvalidCodeError(src []byte, err error, bodyStartLine int, segments []generatedSegment) *utils.InvalidCodeError {
+	invalidErr := &utils.InvalidCodeError{Src: src, Msg: err.Error()}
+	errList, ok := err.(scanner.ErrorList)
+	if !ok || len(errList) == 0 {
+		return invalidErr
+	}
+	pos := errList[0].Pos
+	invalidErr.Line, invalidErr.Column, invalidErr.Msg = pos.Line, pos.Column, errList[0].Msg
+
+	// find the segment containing the error line
+	bodyLine := pos.Line - 1 - bodyStartLine
+	for idx := len(segments) - 1; idx >= 0; idx-- {
+		if segments[idx].StartLine <= bodyLine {
+			invalidErr.Type = segments[idx].Type.Name
+			invalidErr.Plugin = segments[idx].Plugin
+			invalidErr.Source = segments[idx].Type.Pos
+			break
+		}
+	}
+
+	// numbered lines around the error line
+	lines := strings.Split(string(src), "\n")
+	context := bytes.NewBuffer(nil)
+	for line := pos.Line - invalidCodeContextLines; line <= pos.Line+invalidCodeContextLines; line++ {
+		if line < 1 || line > len(lines) {
+			continue
+		}
+		marker := " "
+		if line == pos.Line {
+			marker = ">"
+		}
+		context.WriteString(fmt.Sprintf("%s %5d | %s\n", marker, line, lines[line-1]))
+	}
+	invalidErr.Context = context.String()
+	return invalidErr
+}
+
+// displayPath returns path relative to working directory if possible.
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
 }
 
 func (d *Derive) Help(pluginID []string) error {
@@ -331,6 +1114,155 @@ func (d *Derive) Help(pluginID []string) error {
 	return nil
 }
 
+// pluginEnabled reports whether code is generated with the plugin, by --plugins and --skip-plugins.
+func (d *Derive) pluginEnabled(pluginID string) bool {
+	for _, skipped := range d.SkipPlugins {
+		if skipped == pluginID {
+			return false
+		}
+	}
+	if len(d.EnabledPlugins) == 0 {
+		return true
+	}
+	for _, enabled := range d.EnabledPlugins {
+		if enabled == pluginID {
+			return true
+		}
+	}
+	return false
+}
+
+// filtersPlugins reports whether annotations of any plugin may be skipped.
+func (d *Derive) filtersPlugins() bool {
+	return len(d.EnabledPlugins) > 0 || len(d.SkipPlugins) > 0 || d.WarnUnknownPlugins
+}
+
+func (d *Derive) warn(err error) {
+	d.warningsMu.Lock()
+	d.warnings.Add(err)
+	d.warningsMu.Unlock()
+}
+
+// skippedPlugin is a disabled plugin of a type, with the identifiers it would generate for the type.
+type skippedPlugin struct {
+	Plugin string
+	// nil if the code can't be generated
+	Identifiers []string
+}
+
+// skipOutput records the disabled plugin of typ in file, for the output the type is generated into during a run.
+func (d *Derive) skipOutput(file string, settings *Settings, typ TypeInfo, plg plugin.Entry) {
+	d.skippedOutputsMu.Lock()
+	recording := d.skippedOutputs != nil
+	d.skippedOutputsMu.Unlock()
+	if !recording {
+		return
+	}
+	filename, err := filepath.Abs(filepath.Join(filepath.Dir(file), settings.OutputName(typ)))
+	if err != nil {
+		return
+	}
+	skipped := skippedPlugin{Plugin: plg.Plugin, Identifiers: d.pluginIdentifiers(settings, typ, plg)}
+	d.skippedOutputsMu.Lock()
+	d.skippedOutputs[filename] = append(d.skippedOutputs[filename], skipped)
+	d.skippedOutputsMu.Unlock()
+}
+
+// pluginIdentifiers returns top-level identifiers the plugin generates for typ, nil if the code can't be generated.
+func (d *Derive) pluginIdentifiers(settings *Settings, typ TypeInfo, plg plugin.Entry) []string {
+	p, err := d.GetPlugin(plg.Plugin)
+	if err != nil {
+		return nil
+	}
+	d.resolveOptions(settings, plg)
+	if err := p.Describe().Validate(plg.Opts); err != nil {
+		return nil
+	}
+	buf := bytes.NewBufferString("package p\n")
+	typeInfo := plugin.TypeInfo{Name: typ.Name, Ast: typ.Ast, Assigned: typ.Assigned, Fset: typ.Fset}
+	if _, err := p.GenerateTo(buf, typ.Env, typeInfo, *plg.Opts); err != nil {
+		return nil
+	}
+	identifiers, err := sourceIdentifiers(buf.Bytes())
+	if err != nil {
+		return nil
+	}
+	if identifiers == nil {
+		identifiers = []string{}
+	}
+	return identifiers
+}
+
+// heldSkippedPlugins returns disabled plugins whose code is held by the existing output, in order.
+// Nothing is held if the output doesn't exist.
+// Plugins whose code can't be generated are assumed to be held.
+func (d *Derive) heldSkippedPlugins(filename string, existing []byte) []string {
+	d.skippedOutputsMu.Lock()
+	skipped := d.skippedOutputs[filename]
+	d.skippedOutputsMu.Unlock()
+	if len(skipped) == 0 || existing == nil {
+		return nil
+	}
+	declared, err := sourceIdentifiers(existing)
+	declaredSet := utils.NewStrSetFromSlice(declared)
+	plugins := utils.NewStrSet(0)
+	for _, plg := range skipped {
+		held := err != nil || plg.Identifiers == nil
+		for _, ident := range plg.Identifiers {
+			held = held || declaredSet.Contains(ident)
+		}
+		if held {
+			plugins.Append(plg.Plugin)
+		}
+	}
+	held := plugins.ToSlice()
+	sort.Strings(held)
+	return held
+}
+
+// reportWarnings reports and clears warnings found so far.
+func (d *Derive) reportWarnings() {
+	d.warningsMu.Lock()
+	warnings := d.warnings
+	d.warnings = nil
+	d.warningsMu.Unlock()
+	warnings.Sort()
+	for _, err := range warnings {
+		d.reporter.Diagnostic(err, utils.SeverityWarning)
+	}
+}
+
+// parseOpts parses values of --opt into options of each plugin.
+func (d *Derive) parseOpts(values []string) (map[string]*plugin.Options, error) {
+	pluginOptions := make(map[string]*plugin.Options)
+	for _, value := range values {
+		idx := strings.Index(value, ".")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid --opt %#v, expect plugin.Key=Value, plugin.Flag or plugin.!Flag", value)
+		}
+		pluginID := value[:idx]
+		plg, err := d.GetPlugin(pluginID)
+		if err != nil {
+			return nil, fmt.Errorf("--opt %#v: %s", value, err.Error())
+		}
+		opts, err := plugin.ParseOptions(value[idx+1:])
+		if err != nil {
+			return nil, fmt.Errorf("--opt %#v: %s", value, err.Error())
+		}
+		// mistakes in command line are reported once, instead of for each type
+		if err := plg.Describe().ValidatePartial(opts); err != nil {
+			return nil, fmt.Errorf("--opt %#v: %s", value, err.Error())
+		}
+		if pluginOptions[pluginID] == nil {
+			pluginOptions[pluginID] = plugin.NewOptions()
+		}
+		if err := pluginOptions[pluginID].Merge(opts); err != nil {
+			return nil, fmt.Errorf("--opt %#v: %s", value, err.Error())
+		}
+	}
+	return pluginOptions, nil
+}
+
 func (d *Derive) ValidatePluginOptions(pluginID string, opts *plugin.Options) error {
 	plugin, err := d.GetPlugin(pluginID)
 	if err != nil {
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"bytes"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nextzhou/goderive/plugin"
+	"github.com/nextzhou/goderive/plugin/set"
+	"github.com/nextzhou/goderive/plugin/slice"
+	"github.com/nextzhou/goderive/utils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type brokenPlugin struct{}
+
+func (brokenPlugin) Describe() plugin.Description {
+	return plugin.Description{Identity: "broken", Effect: "generate invalid code"}
+}
+
+func (brokenPlugin) GenerateTo(w io.Writer, env plugin.Env, typeInfo plugin.TypeInfo, opt plugin.Options) (plugin.Prerequisites, error) {
+	_, err := io.WriteString(w, "\nfunc (x "+typeInfo.Name+") Broken( {\n}\n")
+	return plugin.MakePrerequisites(), err
+}
+
+func TestGeneratePackage(t *testing.T) {
+	Convey("generate package", t, func() {
+		derive := NewDerive()
+		derive.RegisterPlugin(brokenPlugin{})
+
+		src := []byte("package foo\n\n// derive-broken\ntype Foo int\n")
+		types, err := ExtractTypes("foo.go", src)
+		So(err, ShouldBeNil)
+		So(types, ShouldHaveLength, 1)
+
+		Convey("invalid code", func() {
+			_, err := derive.GeneratePackage(types)
+			So(err, ShouldHaveSameTypeAs, &utils.InvalidCodeError{})
+			invalidErr := err.(*utils.InvalidCodeError)
+			So(invalidErr.Plugin, ShouldEqual, "broken")
+			So(invalidErr.Type, ShouldEqual, "Foo")
+			So(invalidErr.Source.String(), ShouldEqual, "foo.go:3:1")
+			So(invalidErr.Line, ShouldEqual, 6)
+			So(invalidErr.Context, ShouldContainSubstring, ">     6 | func (x Foo) Broken( {")
+		})
+
+		Convey("conflicting packages", func() {
+			mainTypes, err := ExtractTypes("main.go", []byte("package main\n\n// derive-broken\ntype Bar int\n\n// derive-broken\ntype Baz int\n"))
+			So(err, ShouldBeNil)
+			_, err = derive.GenerateOutput(".", append(types, mainTypes...))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `main.go:3:1: type Bar: found packages foo (foo.go) and main (main.go), both generated into "derived.gen.go"`)
+		})
+	})
+}
+
+func TestRunStdin(t *testing.T) {
+	Convey("run stdin", t, func() {
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+		derive.StdinFilename = "foo.go"
+		var stdout, stderr bytes.Buffer
+		reporter, err := NewReporter("text", &stderr, &stderr)
+		So(err, ShouldBeNil)
+		derive.reporter = reporter
+
+		Convey("generated code", func() {
+			err := derive.RunStdin(strings.NewReader("package foo\n\n// derive-set\ntype Int int\n"), &stdout)
+			So(stderr.String(), ShouldBeEmpty)
+			So(err, ShouldBeNil)
+			So(stdout.String(), ShouldStartWith, utils.HeaderComment)
+			So(stdout.String(), ShouldContainSubstring, "type IntSet struct {")
+		})
+
+		Convey("no derived type", func() {
+			err := derive.RunStdin(strings.NewReader("package foo\n\ntype Int int\n"), &stdout)
+			So(err, ShouldBeNil)
+			So(stdout.String(), ShouldBeEmpty)
+		})
+
+		Convey("syntax error", func() {
+			err := derive.RunStdin(strings.NewReader("package foo\n\ntype\n"), &stdout)
+			So(err, ShouldNotBeNil)
+			So(stdout.String(), ShouldBeEmpty)
+			So(stderr.String(), ShouldStartWith, "foo.go:3:6: ")
+		})
+	})
+}
+
+func TestCommandLineOptions(t *testing.T) {
+	Convey("options from command line", t, func() {
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+		src := []byte("package foo\n\n// derive-set: Order=Key\ntype A int\n\n// derive-set\ntype B int\n")
+		orders := func() []string {
+			types, err := derive.ExtractSourceTypes("foo.go", src)
+			So(err, ShouldBeNil)
+			var orders []string
+			for _, typ := range types {
+				typ.Plugins.ForEach(func(plg plugin.Entry) {
+					orders = append(orders, string(plg.Opts.MustGetValue("Order")))
+				})
+			}
+			return orders
+		}
+
+		var err error
+		derive.cmdPluginOptions, err = derive.parseOpts([]string{"set.Order=Append", "set.Rename=Names"})
+		So(err, ShouldBeNil)
+		So(derive.cmdPluginOptions["set"].String(), ShouldEqual, "Order=Append;Rename=Names")
+
+		Convey("annotations take precedence", func() {
+			So(orders(), ShouldResemble, []string{"Key", "Append"})
+		})
+
+		Convey("override annotations", func() {
+			derive.OptsOverride = true
+			So(orders(), ShouldResemble, []string{"Append", "Append"})
+		})
+
+		Convey("invalid", func() {
+			_, err := derive.parseOpts([]string{"Order=Append"})
+			So(err, ShouldNotBeNil)
+			_, err = derive.parseOpts([]string{"unknown.Order=Append"})
+			So(err, ShouldNotBeNil)
+			_, err = derive.parseOpts([]string{"set.Export", "set.!Export"})
+			So(err, ShouldNotBeNil)
+			_, err = derive.parseOpts([]string{"set.Bogus"})
+			So(err, ShouldBeError, `--opt "set.Bogus": unexpected flag "Bogus"`)
+			_, err = derive.parseOpts([]string{"set.Order=Bogus"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPluginSelection(t *testing.T) {
+	Convey("plugin selection", t, func() {
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{}, brokenPlugin{})
+		src := []byte("package foo\n\n// derive-set\n// derive-broken\ntype A int\n\n// derive-broken\ntype B int\n\n// derive-unknown\ntype C int\n")
+
+		Convey("unknown plugin", func() {
+			_, err := derive.ExtractSourceTypes("foo.go", src)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `foo.go:10:1: type C: plugin unknown: unsupported plugin "unknown"`)
+		})
+
+		Convey("skipped plugins", func() {
+			derive.SkipPlugins = []string{"broken"}
+			derive.WarnUnknownPlugins = true
+			types, err := derive.ExtractSourceTypes("foo.go", src)
+			So(err, ShouldBeNil)
+			So(types, ShouldHaveLength, 1)
+			So(types[0].Name, ShouldEqual, "A")
+			So(types[0].Plugins.Len(), ShouldEqual, 1)
+			So(types[0].Plugins.Index(0).Plugin, ShouldEqual, "set")
+			So(derive.warnings, ShouldHaveLength, 3)
+		})
+
+		Convey("enabled plugins", func() {
+			derive.EnabledPlugins = []string{"broken"}
+			derive.WarnUnknownPlugins = true
+			types, err := derive.ExtractSourceTypes("foo.go", src)
+			So(err, ShouldBeNil)
+			So(types, ShouldHaveLength, 2)
+			So(types[0].Plugins.Index(0).Plugin, ShouldEqual, "broken")
+			So(derive.pluginEnabled("set"), ShouldBeFalse)
+		})
+	})
+}
+
+func TestWriteFiles(t *testing.T) {
+	Convey("write files", t, func() {
+		dir, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		derive := NewDerive()
+		entries := func() []string {
+			infos, err := ioutil.ReadDir(dir)
+			So(err, ShouldBeNil)
+			var names []string
+			for _, info := range infos {
+				names = append(names, info.Name())
+			}
+			return names
+		}
+		So(ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte("old"), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(dir, "stale.go"), []byte("old"), 0644), ShouldBeNil)
+
+		Convey("renamed from temporary files", func() {
+			err := derive.WriteFiles([]GeneratedFile{
+				{Path: dir, Filename: filepath.Join(dir, "a.go"), Src: []byte("a")},
+				{Path: dir, Filename: filepath.Join(dir, "b.go"), Src: []byte("b")},
+				{Path: dir, Filename: filepath.Join(dir, "stale.go")},
+				{Path: dir, Filename: filepath.Join(dir, "gone.go")},
+			})
+			So(err, ShouldBeNil)
+			So(entries(), ShouldResemble, []string{"a.go", "b.go"})
+			src, err := ioutil.ReadFile(filepath.Join(dir, "a.go"))
+			So(err, ShouldBeNil)
+			So(string(src), ShouldEqual, "a")
+		})
+
+		Convey("staged files are removed if rename fails", func() {
+			// a non-empty directory can't be replaced by a file
+			So(os.MkdirAll(filepath.Join(dir, "b.go", "x"), 0755), ShouldBeNil)
+			err := derive.WriteFiles([]GeneratedFile{
+				{Path: dir, Filename: filepath.Join(dir, "b.go"), Src: []byte("b")},
+				{Path: dir, Filename: filepath.Join(dir, "c.go"), Src: []byte("c")},
+				{Path: dir, Filename: filepath.Join(dir, "stale.go")},
+			})
+			So(err, ShouldNotBeNil)
+			So(entries(), ShouldResemble, []string{"a.go", "b.go", "stale.go"})
+		})
+
+		Convey("delete errors are returned", func() {
+			So(os.MkdirAll(filepath.Join(dir, "stale_dir.go", "x"), 0755), ShouldBeNil)
+			err := derive.WriteFiles([]GeneratedFile{
+				{Path: dir, Filename: filepath.Join(dir, "stale_dir.go")},
+				{Path: dir, Filename: filepath.Join(dir, "stale.go")},
+			})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "stale_dir.go")
+			So(entries(), ShouldResemble, []string{"a.go", "stale_dir.go"})
+		})
+	})
+}
+
+func TestRunDebugFile(t *testing.T) {
+	Convey("debug file of invalid code", t, func() {
+		dir, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{}, brokenPlugin{})
+		var stderr bytes.Buffer
+		derive.reporter, err = NewReporter("text", &stderr, &stderr)
+		So(err, ShouldBeNil)
+		source := filepath.Join(dir, "foo.go")
+		debugFile := filepath.Join(dir, "derived.gen.go.invalid")
+		So(ioutil.WriteFile(source, []byte("package foo\n\n// derive-broken\ntype Foo int\n"), 0644), ShouldBeNil)
+
+		Convey("not written by checks", func() {
+			derive.Check = true
+			So(derive.Run([]string{dir}), ShouldNotBeNil)
+			_, err := os.Stat(debugFile)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("removed once the output is generated", func() {
+			So(derive.Run([]string{dir}), ShouldNotBeNil)
+			src, err := ioutil.ReadFile(debugFile)
+			So(err, ShouldBeNil)
+			So(string(src), ShouldContainSubstring, "Broken(")
+			So(stderr.String(), ShouldContainSubstring, "generated code was written to")
+
+			So(ioutil.WriteFile(source, []byte("package foo\n\n// derive-set\ntype Foo int\n"), 0644), ShouldBeNil)
+			So(derive.Run([]string{dir}), ShouldBeNil)
+			_, err = os.Stat(debugFile)
+			So(os.IsNotExist(err), ShouldBeTrue)
+			_, err = os.Stat(filepath.Join(dir, "derived.gen.go"))
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestRunStaleOutputs(t *testing.T) {
+	Convey("stale outputs", t, func() {
+		root, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+		derive.Output = "types.go"
+		var stderr bytes.Buffer
+		derive.reporter, err = NewReporter("text", &stderr, &stderr)
+		So(err, ShouldBeNil)
+		for _, dir := range []string{"a", "b"} {
+			So(os.MkdirAll(filepath.Join(root, dir), 0755), ShouldBeNil)
+		}
+		So(ioutil.WriteFile(filepath.Join(root, "a", "a.go"), []byte("package a\n\n// derive-set\ntype A int\n"), 0644), ShouldBeNil)
+		handWritten := []byte("package b\n\ntype B int\n")
+		So(ioutil.WriteFile(filepath.Join(root, "b", "types.go"), handWritten, 0644), ShouldBeNil)
+
+		Convey("hand-written files are never deleted", func() {
+			So(derive.Run([]string{root + "/..."}), ShouldBeNil)
+			derive.Force = true
+			So(derive.Run([]string{root + "/..."}), ShouldBeNil)
+			src, err := ioutil.ReadFile(filepath.Join(root, "b", "types.go"))
+			So(err, ShouldBeNil)
+			So(string(src), ShouldEqual, string(handWritten))
+			_, err = os.Stat(filepath.Join(root, "a", "types.go"))
+			So(err, ShouldBeNil)
+		})
+
+		Convey("outputs out of include patterns are kept", func() {
+			So(ioutil.WriteFile(filepath.Join(root, "b", "b.go"), []byte("package b\n\n// derive-set\ntype C int\n"), 0644), ShouldBeNil)
+			So(os.Remove(filepath.Join(root, "b", "types.go")), ShouldBeNil)
+			So(ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644), ShouldBeNil)
+			So(derive.Run([]string{root + "/..."}), ShouldBeNil)
+			So(os.Remove(filepath.Join(root, "a", "types.go")), ShouldBeNil)
+			// settings are loaded once for each directory
+			derive.settings = nil
+			derive.Includes = []string{"a/**"}
+			So(derive.Run([]string{root + "/..."}), ShouldBeNil)
+			_, err := os.Stat(filepath.Join(root, "a", "types.go"))
+			So(err, ShouldBeNil)
+			_, err = os.Stat(filepath.Join(root, "b", "types.go"))
+			So(err, ShouldBeNil)
+		})
+
+		Convey("generated files are deleted", func() {
+			So(derive.Run([]string{root + "/..."}), ShouldBeNil)
+			So(ioutil.WriteFile(filepath.Join(root, "a", "a.go"), []byte("package a\n\ntype A int\n"), 0644), ShouldBeNil)
+			So(derive.Run([]string{root + "/..."}), ShouldBeNil)
+			_, err := os.Stat(filepath.Join(root, "a", "types.go"))
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+}
+
+func TestListGoFiles(t *testing.T) {
+	Convey("list go files", t, func() {
+		dir, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		derive := NewDerive()
+		derive.Output = "types.go"
+		// a hand-written file named as the output variant of the current target
+		handWritten := "types_" + build.Default.GOOS + ".go"
+		files := map[string]string{
+			"a.go":          "package a\n",
+			"types.go":      utils.HeaderComment + "package a\n",
+			"types_test.go": utils.HeaderComment + "package a\n",
+			handWritten:     "package a\n",
+		}
+		for name, src := range files {
+			So(ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644), ShouldBeNil)
+		}
+		goFiles, err := derive.ListGoFiles(dir, false)
+		So(err, ShouldBeNil)
+		So(goFiles, ShouldContain, filepath.Join(dir, "a.go"))
+		So(goFiles, ShouldNotContain, filepath.Join(dir, "types.go"))
+		So(goFiles, ShouldNotContain, filepath.Join(dir, "types_test.go"))
+		So(goFiles, ShouldContain, filepath.Join(dir, handWritten))
+	})
+}
+
+func TestRunStdout(t *testing.T) {
+	Convey("stdout with paths", t, func() {
+		root, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+		derive.Stdout = true
+		derive.reporter, err = NewReporter("text", ioutil.Discard, ioutil.Discard)
+		So(err, ShouldBeNil)
+		for _, dir := range []string{"a", "b"} {
+			So(os.MkdirAll(filepath.Join(root, dir), 0755), ShouldBeNil)
+			src := "package " + dir + "\n\n// derive-set\ntype T int\n"
+			So(ioutil.WriteFile(filepath.Join(root, dir, dir+".go"), []byte(src), 0644), ShouldBeNil)
+		}
+
+		err = derive.Run([]string{root + "/..."})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "2 files would be generated")
+	})
+}
+
+func TestRunPluginSelection(t *testing.T) {
+	Convey("run with plugin selection", t, func() {
+		dir, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{}, slice.Slice{})
+		var stderr bytes.Buffer
+		derive.reporter, err = NewReporter("text", &stderr, &stderr)
+		So(err, ShouldBeNil)
+		output := filepath.Join(dir, "derived.gen.go")
+		So(ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n\n// derive-slice\ntype A []int\n"), 0644), ShouldBeNil)
+		So(derive.Run([]string{dir}), ShouldBeNil)
+		generated, err := ioutil.ReadFile(output)
+		So(err, ShouldBeNil)
+
+		Convey("outputs holding code of skipped plugins are not deleted", func() {
+			derive.EnabledPlugins = []string{"set"}
+			err := derive.Run([]string{dir})
+			So(err, ShouldNotBeNil)
+			So(stderr.String(), ShouldContainSubstring, "skipped, the plugin is disabled")
+			So(stderr.String(), ShouldContainSubstring, "refuse to delete")
+			src, err := ioutil.ReadFile(output)
+			So(err, ShouldBeNil)
+			So(string(src), ShouldEqual, string(generated))
+		})
+
+		Convey("outputs holding code of skipped plugins are not overwritten", func() {
+			So(ioutil.WriteFile(filepath.Join(dir, "b.go"), []byte("package a\n\n// derive-set\ntype B int\n"), 0644), ShouldBeNil)
+			derive.SkipPlugins = []string{"slice"}
+			err := derive.Run([]string{dir})
+			So(err, ShouldNotBeNil)
+			So(stderr.String(), ShouldContainSubstring, "refuse to overwrite")
+			src, err := ioutil.ReadFile(output)
+			So(err, ShouldBeNil)
+			So(string(src), ShouldEqual, string(generated))
+		})
+
+		Convey("outputs holding code of skipped plugins are skipped by checks", func() {
+			derive.EnabledPlugins = []string{"set"}
+			derive.Check = true
+			So(derive.Run([]string{dir}), ShouldBeNil)
+			So(stderr.String(), ShouldNotContainSubstring, "refuse to")
+		})
+
+		Convey("outputs without code of skipped plugins are generated", func() {
+			So(ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n\n// derive-set\ntype A int\n"), 0644), ShouldBeNil)
+			So(derive.Run([]string{dir}), ShouldBeNil)
+			So(ioutil.WriteFile(filepath.Join(dir, "b.go"), []byte("package a\n\n// derive-set\ntype B int\n\n// derive-slice\ntype Bs []int\n"), 0644), ShouldBeNil)
+			derive.SkipPlugins = []string{"slice"}
+			So(derive.Run([]string{dir}), ShouldBeNil)
+			src, err := ioutil.ReadFile(output)
+			So(err, ShouldBeNil)
+			So(string(src), ShouldContainSubstring, "ASet")
+			So(string(src), ShouldContainSubstring, "BSet")
+		})
+
+		Convey("missing outputs are generated", func() {
+			So(os.Remove(output), ShouldBeNil)
+			So(ioutil.WriteFile(filepath.Join(dir, "b.go"), []byte("package a\n\n// derive-set\ntype B int\n"), 0644), ShouldBeNil)
+			derive.SkipPlugins = []string{"slice"}
+			So(derive.Run([]string{dir}), ShouldBeNil)
+			src, err := ioutil.ReadFile(output)
+			So(err, ShouldBeNil)
+			So(string(src), ShouldContainSubstring, "BSet")
+			So(string(src), ShouldNotContainSubstring, "ASlice")
+		})
+	})
+}
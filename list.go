@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/nextzhou/goderive/plugin"
+	"github.com/nextzhou/goderive/utils"
+	"github.com/spf13/cobra"
+)
+
+const FormatTable = "table"
+
+// ListedType is an annotated type reported by the list subcommand.
+type ListedType struct {
+	Position string         `json:"position"`
+	Package  string         `json:"package"`
+	Name     string         `json:"name"`
+	Plugins  []ListedPlugin `json:"plugins"`
+	pos      token.Position
+}
+
+// ListedPlugin is a plugin of a listed type, with options resolved from annotations, configurations and defaults.
+type ListedPlugin struct {
+	Plugin string `json:"plugin"`
+	// options in the format of derive comment
+	Options string              `json:"options"`
+	Flags   map[string]bool     `json:"flags"`
+	Args    map[string][]string `json:"args"`
+}
+
+// ListTypes returns annotated types of go files in inputPaths, ordered by position.
+// Types with invalid options are left out, and their errors are returned.
+func (d *Derive) ListTypes(inputPaths []string) ([]ListedType, utils.ErrorList) {
+	var errs utils.ErrorList
+	if len(inputPaths) == 0 {
+		inputPaths = []string{"."}
+	}
+	files := utils.NewStrSet(0)
+	for _, path := range inputPaths {
+		fs, err := d.ListGoFiles(path, false)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		files.Append(fs...)
+	}
+
+	fileList := files.ToSlice()
+	typesOfFiles := make([][]TypeInfo, len(fileList))
+	errsOfFiles := make([]error, len(fileList))
+	utils.ParallelDo(d.workers(), len(fileList), func(idx int) {
+		typesOfFiles[idx], errsOfFiles[idx] = d.ExtractFileTypes(fileList[idx])
+	})
+
+	var listed []ListedType
+	for idx := range fileList {
+		errs.Add(errsOfFiles[idx])
+		for _, typ := range typesOfFiles[idx] {
+			listed = append(listed, makeListedType(typ))
+		}
+	}
+	sort.SliceStable(listed, func(i, j int) bool {
+		pi, pj := listed[i].pos, listed[j].pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		if pi.Line != pj.Line {
+			return pi.Line < pj.Line
+		}
+		return pi.Column < pj.Column
+	})
+	errs.Sort()
+	return listed, errs
+}
+
+func makeListedType(typ TypeInfo) ListedType {
+	listed := ListedType{
+		Position: typ.Pos.String(),
+		Package:  typ.Env.PkgName,
+		Name:     typ.Name,
+		pos:      typ.Pos,
+	}
+	typ.Plugins.ForEach(func(plg plugin.Entry) {
+		listed.Plugins = append(listed.Plugins, makeListedPlugin(plg))
+	})
+	return listed
+}
+
+func makeListedPlugin(plg plugin.Entry) ListedPlugin {
+	listed := ListedPlugin{
+		Plugin:  plg.Plugin,
+		Options: plg.Opts.String(),
+		Flags:   make(map[string]bool),
+		Args:    make(map[string][]string),
+	}
+	for flag, val := range plg.Opts.Flags {
+		if !val.IsUndefined() {
+			listed.Flags[string(flag)] = val.IsTrue()
+		}
+	}
+	for key, arg := range plg.Opts.Args {
+		values := make([]string, len(arg.Values))
+		for i, value := range arg.Values {
+			values[i] = value.Str()
+		}
+		listed.Args[key] = values
+	}
+	return listed
+}
+
+// WriteListedTypes writes listed types in the format, one line per plugin of a type for table.
+func WriteListedTypes(w io.Writer, format string, listed []ListedType) error {
+	switch format {
+	case FormatTable:
+		table := utils.NewTableWriter(w)
+		table.Append([]string{"POSITION", "TYPE", "PLUGIN", "OPTIONS"})
+		for _, typ := range listed {
+			for _, plg := range typ.Plugins {
+				table.Append([]string{typ.Position, typ.Name, plg.Plugin, plg.Options})
+			}
+		}
+		table.Render()
+		return nil
+	case FormatJSON:
+		if listed == nil {
+			listed = []ListedType{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(listed)
+	default:
+		return &utils.UnsupportedError{Type: "format", Idents: []string{format}}
+	}
+}
+
+func (d *Derive) newListCommand() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "list [path ...]",
+		Short: "List annotated types with their plugins and resolved options",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != FormatTable && format != FormatJSON {
+				return &utils.UnsupportedError{Type: "format", Idents: []string{format}}
+			}
+			listed, errs := d.ListTypes(args)
+			// diagnostics never mix with the listing in stdout
+			reporter, _ := NewReporter(FormatText, os.Stderr, os.Stderr)
+			for _, err := range errs {
+				reporter.Diagnostic(err, utils.SeverityError)
+			}
+			if err := WriteListedTypes(os.Stdout, format, listed); err != nil {
+				return err
+			}
+			if len(errs) > 0 {
+				return fmt.Errorf("%d error(s) found", len(errs))
+			}
+			return nil
+		},
+		SilenceUsage: true,
+	}
+	cmd.SetHelpTemplate(defaultHelpTemplate)
+	cmd.Flags().StringVar(&format, "format", FormatTable, "output format: table or json")
+	cmd.Flags().StringSliceVar(&d.Tags, "tags", nil, "comma separated build tags to select source files, with GOOS and GOARCH from environment")
+	return cmd
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nextzhou/goderive/plugin/set"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestListTypes(t *testing.T) {
+	Convey("list types", t, func() {
+		root, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+		So(ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(root, ConfigFileName), []byte(`{"plugins": {"set": "Rename=Names"}}`), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(root, "b.go"), []byte("package m\n\n// derive-set: Order=Append\ntype Name string\n\n// derive-set: Bogus\ntype Bad int\n"), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(root, "a.go"), []byte("package m\n\ntype ID int\n\n// derive-set\ntype Key string\n"), 0644), ShouldBeNil)
+
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+		listed, errs := derive.ListTypes([]string{root})
+		So(errs, ShouldHaveLength, 1)
+		So(errs[0].Error(), ShouldContainSubstring, `type Bad: plugin set: unexpected flag "Bogus"`)
+		So(listed, ShouldHaveLength, 2)
+
+		So(listed[0].Name, ShouldEqual, "Key")
+		So(listed[0].Package, ShouldEqual, "m")
+		So(listed[0].Position, ShouldEqual, filepath.Join(root, "a.go")+":5:1")
+		So(listed[0].Plugins, ShouldHaveLength, 1)
+		So(listed[0].Plugins[0].Options, ShouldEqual, "Rename=Names;Order=Unstable")
+
+		So(listed[1].Name, ShouldEqual, "Name")
+		So(listed[1].Plugins[0].Options, ShouldEqual, "Order=Append;Rename=Names")
+		So(listed[1].Plugins[0].Args["Order"], ShouldResemble, []string{"Append"})
+
+		Convey("table", func() {
+			var buf bytes.Buffer
+			So(WriteListedTypes(&buf, FormatTable, listed), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, "POSITION")
+			So(buf.String(), ShouldContainSubstring, "Order=Append;Rename=Names")
+		})
+
+		Convey("json", func() {
+			var buf bytes.Buffer
+			So(WriteListedTypes(&buf, FormatJSON, nil), ShouldBeNil)
+			So(buf.String(), ShouldEqual, "[]\n")
+			buf.Reset()
+			So(WriteListedTypes(&buf, FormatJSON, listed), ShouldBeNil)
+			So(buf.String(), ShouldContainSubstring, `"name": "Key"`)
+		})
+
+		Convey("unsupported format", func() {
+			So(WriteListedTypes(new(bytes.Buffer), "yaml", listed), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"sort"
+)
+
+// Manifest records generated outputs, and identifiers emitted by each plugin for each type.
+type Manifest struct {
+	Outputs []ManifestOutput `json:"outputs"`
+}
+
+// ManifestOutput is a generated output file of a package.
+type ManifestOutput struct {
+	// directory of the package
+	Path    string         `json:"path"`
+	Package string         `json:"package"`
+	Output  string         `json:"output"`
+	Types   []ManifestType `json:"types"`
+}
+
+// ManifestType is a source type generated into the output.
+type ManifestType struct {
+	Name     string           `json:"name"`
+	Position string           `json:"position"`
+	Plugins  []ManifestPlugin `json:"plugins"`
+}
+
+// ManifestPlugin is a plugin of the type with resolved options, and the top-level identifiers it emitted.
+type ManifestPlugin struct {
+	ListedPlugin
+	// types, functions, variables and constants, methods are in the form of Type.Method
+	Identifiers []string `json:"identifiers"`
+}
+
+func makeManifestPlugin(plg ListedPlugin, code []byte) ManifestPlugin {
+	identifiers := declaredIdentifiers(code)
+	if identifiers == nil {
+		identifiers = []string{}
+	}
+	return ManifestPlugin{ListedPlugin: plg, Identifiers: identifiers}
+}
+
+// declaredIdentifiers returns top-level identifiers declared in the code generated by a plugin, in declaration order.
+func declaredIdentifiers(code []byte) []string {
+	identifiers, _ := sourceIdentifiers(append([]byte("package p\n"), code...))
+	return identifiers
+}
+
+// sourceIdentifiers returns top-level identifiers declared in the source of a go file, in declaration order.
+func sourceIdentifiers(src []byte) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	var identifiers []string
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			name := decl.Name.Name
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				name = receiverTypeName(decl.Recv.List[0].Type) + "." + name
+			}
+			identifiers = append(identifiers, name)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					identifiers = append(identifiers, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.Name != "_" {
+							identifiers = append(identifiers, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return identifiers, nil
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(expr.X)
+	case *ast.ParenExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// WriteManifest writes the manifest of generated files to filename, ordered by output file names.
+func WriteManifest(filename string, files []GeneratedFile) error {
+	manifest := Manifest{Outputs: []ManifestOutput{}}
+	for _, file := range files {
+		if file.Src == nil || file.manifestTypes == nil {
+			continue
+		}
+		manifest.Outputs = append(manifest.Outputs, ManifestOutput{
+			Path:    displayPath(file.Path),
+			Package: file.pkgName,
+			Output:  displayPath(file.Filename),
+			Types:   file.manifestTypes,
+		})
+	}
+	sort.SliceStable(manifest.Outputs, func(i, j int) bool {
+		return manifest.Outputs[i].Output < manifest.Outputs[j].Output
+	})
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
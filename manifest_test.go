@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nextzhou/goderive/plugin/set"
+	"github.com/nextzhou/goderive/utils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeclaredIdentifiers(t *testing.T) {
+	Convey("declared identifiers", t, func() {
+		code := []byte(`
+type FooSet struct{}
+
+func NewFooSet() *FooSet { return nil }
+
+func (set *FooSet) Len() int { return 0 }
+
+func (s List[T]) Get() T { var t T; return t }
+
+var _, errEmpty = 1, 2
+
+const (
+	A = iota
+	B
+)
+`)
+		So(declaredIdentifiers(code), ShouldResemble, []string{"FooSet", "NewFooSet", "FooSet.Len", "List.Get", "errEmpty", "A", "B"})
+		So(declaredIdentifiers([]byte("func (")), ShouldBeNil)
+	})
+}
+
+func TestManifest(t *testing.T) {
+	Convey("manifest", t, func() {
+		dir, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+		derive.Manifest = filepath.Join(dir, "manifest.json")
+		types, err := derive.ExtractSourceTypes(filepath.Join(dir, "foo.go"), []byte("package foo\n\n// derive-set: Order=Append\ntype Foo int\n"))
+		So(err, ShouldBeNil)
+		files, err := derive.GenerateOutput(dir, types)
+		So(err, ShouldBeNil)
+		So(files, ShouldHaveLength, 1)
+		So(WriteManifest(derive.Manifest, files), ShouldBeNil)
+
+		data, err := ioutil.ReadFile(derive.Manifest)
+		So(err, ShouldBeNil)
+		var manifest Manifest
+		So(json.Unmarshal(data, &manifest), ShouldBeNil)
+		So(manifest.Outputs, ShouldHaveLength, 1)
+		output := manifest.Outputs[0]
+		So(output.Package, ShouldEqual, "foo")
+		So(output.Output, ShouldEqual, filepath.Join(dir, "derived.gen.go"))
+		So(output.Types, ShouldHaveLength, 1)
+		So(output.Types[0].Name, ShouldEqual, "Foo")
+		So(output.Types[0].Position, ShouldEqual, filepath.Join(dir, "foo.go")+":3:1")
+		So(output.Types[0].Plugins, ShouldHaveLength, 1)
+		plg := output.Types[0].Plugins[0]
+		So(plg.Plugin, ShouldEqual, "set")
+		So(plg.Options, ShouldEqual, "Order=Append")
+		So(plg.Identifiers, ShouldContain, "FooSet")
+		So(plg.Identifiers, ShouldContain, "NewFooSet")
+		So(plg.Identifiers, ShouldContain, "FooSet.Append")
+	})
+}
+
+func TestRunManifest(t *testing.T) {
+	Convey("run with manifest", t, func() {
+		root, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+		derive := NewDerive()
+		derive.RegisterPlugin(set.Set{})
+		derive.reporter, err = NewReporter("text", ioutil.Discard, ioutil.Discard)
+		So(err, ShouldBeNil)
+		derive.Manifest = filepath.Join(root, "manifest.json")
+		sources := map[string]string{
+			"a/a.go": "package a\n\n// derive-set\ntype A int\n",
+			// stale output of a package without derived type any more
+			"b/b.go":           "package b\n\ntype B int\n",
+			"b/derived.gen.go": utils.HeaderComment + "package b\n",
+			// failed package
+			"c/c.go": "package c\n\n// derive-set: Unknown\ntype C int\n",
+		}
+		for name, src := range sources {
+			filename := filepath.Join(root, name)
+			So(os.MkdirAll(filepath.Dir(filename), 0755), ShouldBeNil)
+			So(ioutil.WriteFile(filename, []byte(src), 0644), ShouldBeNil)
+		}
+		readManifest := func() Manifest {
+			data, err := ioutil.ReadFile(derive.Manifest)
+			So(err, ShouldBeNil)
+			var manifest Manifest
+			So(json.Unmarshal(data, &manifest), ShouldBeNil)
+			return manifest
+		}
+
+		Convey("not written if the run fails", func() {
+			So(derive.Run([]string{root + "/..."}), ShouldNotBeNil)
+			_, err := os.Stat(derive.Manifest)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("only outputs written are recorded", func() {
+			derive.KeepGoing = true
+			So(derive.Run([]string{root + "/..."}), ShouldNotBeNil)
+			manifest := readManifest()
+			So(manifest.Outputs, ShouldHaveLength, 1)
+			So(manifest.Outputs[0].Output, ShouldEqual, filepath.Join(root, "a", "derived.gen.go"))
+			_, err := os.Stat(filepath.Join(root, "b", "derived.gen.go"))
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("unchanged outputs are recorded", func() {
+			So(os.RemoveAll(filepath.Join(root, "c")), ShouldBeNil)
+			So(derive.Run([]string{root + "/..."}), ShouldBeNil)
+			So(os.Remove(derive.Manifest), ShouldBeNil)
+			So(derive.Run([]string{root + "/..."}), ShouldBeNil)
+			So(readManifest().Outputs, ShouldHaveLength, 1)
+		})
+	})
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Module is a main module, i.e. the current module or one used by the go.work workspace.
+type Module struct {
+	Path string
+	Dir  string
+}
+
+// Modules returns main modules found from the working directory.
+func (d *Derive) Modules() ([]Module, error) {
+	if d.modules != nil {
+		return d.modules, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	modules, err := loadModules(cwd)
+	if err != nil {
+		return nil, err
+	}
+	d.modules = modules
+	return modules, nil
+}
+
+func loadModules(dir string) ([]Module, error) {
+	var modDirs []string
+	if workFile := findGoWork(dir); workFile != "" {
+		src, err := ioutil.ReadFile(workFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, use := range parseWorkUses(src) {
+			if !filepath.IsAbs(use) {
+				use = filepath.Join(filepath.Dir(workFile), use)
+			}
+			modDirs = append(modDirs, use)
+		}
+	} else if root := findModuleRoot(dir); root != "" {
+		modDirs = append(modDirs, root)
+	}
+
+	modules := make([]Module, 0, len(modDirs))
+	for _, modDir := range modDirs {
+		src, err := ioutil.ReadFile(filepath.Join(modDir, "go.mod"))
+		if err != nil {
+			return nil, err
+		}
+		modPath := parseModulePath(src)
+		if modPath == "" {
+			return nil, fmt.Errorf("%s: no module declaration", filepath.Join(modDir, "go.mod"))
+		}
+		modules = append(modules, Module{Path: modPath, Dir: modDir})
+	}
+	return modules, nil
+}
+
+// findModuleRoot returns the nearest directory containing go.mod from dir up, or an empty string if there is none.
+func findModuleRoot(dir string) string {
+	for {
+		if isFile(filepath.Join(dir, "go.mod")) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// findGoWork returns the go.work file in effect like the go tool, or an empty string if workspace mode is off.
+func findGoWork(dir string) string {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return ""
+	case "":
+	default:
+		return gowork
+	}
+	for {
+		if isFile(filepath.Join(dir, "go.work")) {
+			return filepath.Join(dir, "go.work")
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func isFile(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && !stat.IsDir()
+}
+
+// parseModulePath returns the path in module directive of go.mod
+func parseModulePath(src []byte) string {
+	for _, line := range strings.Split(string(src), "\n") {
+		fields := strings.Fields(stripModComment(line))
+		if len(fields) == 2 && fields[0] == "module" {
+			return unquoteModPath(fields[1])
+		}
+	}
+	return ""
+}
+
+// parseWorkUses returns directories in use directives of go.work
+func parseWorkUses(src []byte) []string {
+	var uses []string
+	inBlock := false
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(stripModComment(line))
+		if inBlock {
+			if line == ")" {
+				inBlock = false
+			} else if line != "" {
+				uses = append(uses, unquoteModPath(line))
+			}
+			continue
+		}
+		fields := strings.Fields(strings.Replace(line, "(", " ( ", 1))
+		if len(fields) < 2 || fields[0] != "use" {
+			continue
+		}
+		rest := strings.TrimSpace(line[len("use"):])
+		if rest == "(" {
+			inBlock = true
+		} else if rest != "" {
+			uses = append(uses, unquoteModPath(rest))
+		}
+	}
+	return uses
+}
+
+func stripModComment(line string) string {
+	if idx := strings.Index(line, "//"); idx >= 0 {
+		return line[:idx]
+	}
+	return line
+}
+
+func unquoteModPath(s string) string {
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		return unquoted
+	}
+	return s
+}
+
+// isImportPath reports whether path is written as an import path rather than a file system path.
+func isImportPath(path string) bool {
+	return !filepath.IsAbs(path) && !strings.HasPrefix(path, ".")
+}
+
+// ResolveImportPath returns the directory of the package in main modules, or in GOPATH as a fallback.
+func (d *Derive) ResolveImportPath(importPath string) (string, error) {
+	modules, err := d.Modules()
+	if err != nil {
+		return "", err
+	}
+	// the innermost module providing the package
+	var found *Module
+	for i, module := range modules {
+		if importPath != module.Path && !strings.HasPrefix(importPath, module.Path+"/") {
+			continue
+		}
+		if found == nil || len(module.Path) > len(found.Path) {
+			found = &modules[i]
+		}
+	}
+	if found != nil {
+		// packages of nested modules, which are not main modules, are not in the module, as skipSubDir does
+		dir := found.Dir
+		for _, elem := range strings.Split(strings.TrimPrefix(importPath, found.Path), "/") {
+			if elem == "" {
+				continue
+			}
+			if isFile(filepath.Join(dir, elem, "go.mod")) {
+				return "", fmt.Errorf("main module %s does not contain package %s", found.Path, importPath)
+			}
+			dir = filepath.Join(dir, elem)
+		}
+		return dir, nil
+	}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		dir := filepath.Join(gopath, "src", filepath.FromSlash(importPath))
+		if stat, err := os.Stat(dir); err == nil && stat.IsDir() {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("cannot find package %#v in main modules or GOPATH", importPath)
+}
+
+// skipSubDir reports whether the sub-directory is ignored when listing recursively, the way the go tool does.
+func skipSubDir(dir string, name string) bool {
+	if name == "testdata" || strings.HasPrefix(name, "_") {
+		return true
+	}
+	// nested module
+	return isFile(filepath.Join(dir, name, "go.mod"))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseModFiles(t *testing.T) {
+	Convey("parse go.mod and go.work", t, func() {
+		So(parseModulePath([]byte("// comment\nmodule example.com/m // trailing\n\ngo 1.20\n")), ShouldEqual, "example.com/m")
+		So(parseModulePath([]byte("module \"example.com/quoted\"\n")), ShouldEqual, "example.com/quoted")
+		So(parseModulePath([]byte("go 1.20\n")), ShouldBeEmpty)
+
+		So(parseWorkUses([]byte("go 1.20\n\nuse ./a\nuse(\n\t./b // b\n\t\"./c\"\n)\n")), ShouldResemble, []string{"./a", "./b", "./c"})
+	})
+}
+
+func TestResolveImportPath(t *testing.T) {
+	Convey("resolve import path", t, func() {
+		root, err := ioutil.TempDir("", "goderive")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(root)
+		for _, dir := range []string{"a/pkg/testdata", "a/pkg/_hidden", "a/pkg/nested", "b"} {
+			So(os.MkdirAll(filepath.Join(root, dir), 0755), ShouldBeNil)
+		}
+		files := map[string]string{
+			"go.work":             "go 1.20\n\nuse (\n\t./a\n\t./b\n)\n",
+			"a/go.mod":            "module example.com/a\n",
+			"a/pkg/pkg.go":        "package pkg\n",
+			"a/pkg/testdata/x.go": "package x\n",
+			"a/pkg/_hidden/x.go":  "package x\n",
+			"a/pkg/nested/go.mod": "module example.com/a/pkg/nested\n",
+			"a/pkg/nested/x.go":   "package nested\n",
+			"b/go.mod":            "module example.com/b\n",
+			"b/b.go":              "package b\n",
+		}
+		for name, content := range files {
+			So(ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644), ShouldBeNil)
+		}
+		modules, err := loadModules(filepath.Join(root, "a", "pkg"))
+		So(err, ShouldBeNil)
+		So(modules, ShouldResemble, []Module{
+			{Path: "example.com/a", Dir: filepath.Join(root, "a")},
+			{Path: "example.com/b", Dir: filepath.Join(root, "b")},
+		})
+
+		derive := NewDerive()
+		derive.modules = modules
+		dir, err := derive.ResolveImportPath("example.com/a/pkg")
+		So(err, ShouldBeNil)
+		So(dir, ShouldEqual, filepath.Join(root, "a", "pkg"))
+		_, err = derive.ResolveImportPath("example.com/c")
+		So(err, ShouldNotBeNil)
+		_, err = derive.ResolveImportPath("example.com/a/pkg/nested")
+		So(err, ShouldBeError, "main module example.com/a does not contain package example.com/a/pkg/nested")
+
+		Convey("skip nested modules, testdata and _-prefixed directories", func() {
+			goFiles, err := derive.ListGoFiles("example.com/a/...", false)
+			So(err, ShouldBeNil)
+			So(goFiles, ShouldResemble, []string{filepath.Join(root, "a", "pkg", "pkg.go")})
+
+			goFiles, err = derive.ListGoFiles("example.com/b", false)
+			So(err, ShouldBeNil)
+			So(goFiles, ShouldResemble, []string{filepath.Join(root, "b", "b.go")})
+		})
+	})
+}
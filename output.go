@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/nextzhou/goderive/utils"
+)
+
+const (
+	testSuffix         = "_test.go"
+	externalTestSuffix = "_external_test.go"
+)
+
+// output modes
+const (
+	// a single output file for each package
+	OutputModePackage = "package"
+	// an output file for each source file
+	OutputModePerFile = "per-file"
+)
+
+// placeholder of the source file name in pattern of per-file output
+const sourceNamePlaceholder = "{name}"
+
+// OutputName returns the output file name of the type in the settings.
+func (s *Settings) OutputName(typ TypeInfo) string {
+	if s.OutputMode == OutputModePerFile {
+		return perFileOutputName(s.OutputPattern, typ.File, typ.Test)
+	}
+	return OutputName(s.Output, typ.Constraint, typ.Test, typ.Test && strings.HasSuffix(typ.Env.PkgName, "_test"))
+}
+
+// IsOutputName reports whether name may be an output file in either mode, so that switching modes
+// leaves no stale file behind.
+func (s *Settings) IsOutputName(name string) bool {
+	return isOutputName(s.Output, name) || isPerFileOutputName(s.OutputPattern, name)
+}
+
+func validateOutputMode(mode string) error {
+	if mode != OutputModePackage && mode != OutputModePerFile {
+		return &utils.UnsupportedError{Type: "output mode", Idents: []string{mode}}
+	}
+	return nil
+}
+
+// validateOutputPattern checks that names from pattern never collide with source files or test files.
+func validateOutputPattern(pattern string) error {
+	if strings.Count(pattern, sourceNamePlaceholder) != 1 || !strings.HasSuffix(pattern, ".go") ||
+		strings.HasSuffix(pattern, testSuffix) || pattern == sourceNamePlaceholder+".go" || strings.Contains(pattern, "/") {
+		return fmt.Errorf("invalid output pattern %#v, it should contain %s once and end with .go, e.g. %s_derived.gen.go",
+			pattern, sourceNamePlaceholder, sourceNamePlaceholder)
+	}
+	return nil
+}
+
+// perFileOutputName returns the output file name of types from the source file, in per-file mode.
+// The name of foo.go and foo_test.go is "foo".
+func perFileOutputName(pattern, source string, test bool) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(source), ".go"), "_test")
+	output := strings.Replace(pattern, sourceNamePlaceholder, name, 1)
+	if test {
+		output = strings.TrimSuffix(output, ".go") + testSuffix
+	}
+	return output
+}
+
+// isPerFileOutputName reports whether name is an output file name from pattern.
+func isPerFileOutputName(pattern, name string) bool {
+	idx := strings.Index(pattern, sourceNamePlaceholder)
+	if idx < 0 {
+		return false
+	}
+	if strings.HasSuffix(name, testSuffix) {
+		name = strings.TrimSuffix(name, testSuffix) + ".go"
+	}
+	prefix, suffix := pattern[:idx], pattern[idx+len(sourceNamePlaceholder):]
+	return len(name) > len(prefix)+len(suffix) && strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix)
+}
+
+// OutputName returns the output file name of types from files with the build constraint, in a test file or not.
+// Types in external test packages are generated into a separate test file, e.g. derived.gen_external_test.go.
+func OutputName(output, constraint string, test, externalTest bool) string {
+	name := output
+	if constraint != "" {
+		name = outputVariant(name, constraint)
+	}
+	switch {
+	case externalTest:
+		name = strings.TrimSuffix(name, ".go") + externalTestSuffix
+	case test:
+		name = strings.TrimSuffix(name, ".go") + testSuffix
+	}
+	return name
+}
+
+// isOutputName reports whether name is one of the output file names of output.
+func isOutputName(output, name string) bool {
+	if strings.HasSuffix(name, externalTestSuffix) {
+		name = strings.TrimSuffix(name, externalTestSuffix) + ".go"
+	} else if strings.HasSuffix(name, testSuffix) {
+		name = strings.TrimSuffix(name, testSuffix) + ".go"
+	}
+	return name == output || isOutputVariant(output, name)
+}
+
+// outputVariant returns the output file name for a variant of generated code, e.g.
+// derived_linux-and-amd64.gen.go for constraint "linux && amd64" of output derived.gen.go.
+// Words of variant are joined with '-', so that the name never implies another GOOS/GOARCH constraint.
+func outputVariant(output, variant string) string {
+	words := strings.FieldsFunc(variant, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '!' && r != '&' && r != '|'
+	})
+	var parts []string
+	for _, word := range words {
+		word = strings.NewReplacer("&&", "-and-", "||", "-or-", "!", "-not-").Replace(word)
+		for _, part := range strings.Split(word, "-") {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	stem, ext := splitOutputExt(output)
+	return stem + "_" + strings.Join(parts, "-") + ext
+}
+
+// isOutputVariant reports whether name is a variant of output.
+func isOutputVariant(output, name string) bool {
+	stem, ext := splitOutputExt(output)
+	return len(name) > len(stem)+1+len(ext) && strings.HasPrefix(name, stem+"_") && strings.HasSuffix(name, ext)
+}
+
+func splitOutputExt(output string) (stem, ext string) {
+	ext = filepath.Ext(output)
+	if strings.HasSuffix(output, ".gen"+ext) {
+		ext = ".gen" + ext
+	}
+	return strings.TrimSuffix(output, ext), ext
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOutputName(t *testing.T) {
+	Convey("output name", t, func() {
+		So(OutputName("derived.gen.go", "", false, false), ShouldEqual, "derived.gen.go")
+		So(OutputName("derived.gen.go", "", true, false), ShouldEqual, "derived.gen_test.go")
+		So(OutputName("derived.gen.go", "", true, true), ShouldEqual, "derived.gen_external_test.go")
+		So(OutputName("derived.gen.go", "linux", true, true), ShouldEqual, "derived_linux.gen_external_test.go")
+
+		So(isOutputName("derived.gen.go", "derived.gen.go"), ShouldBeTrue)
+		So(isOutputName("derived.gen.go", "derived.gen_test.go"), ShouldBeTrue)
+		So(isOutputName("derived.gen.go", "derived_linux.gen_external_test.go"), ShouldBeTrue)
+		So(isOutputName("derived.gen.go", "derived_test.go"), ShouldBeFalse)
+		So(isOutputName("derived.gen.go", "foo_test.go"), ShouldBeFalse)
+	})
+}
+
+func TestOutputVariant(t *testing.T) {
+	Convey("output variant", t, func() {
+		So(outputVariant("derived.gen.go", "linux && amd64"), ShouldEqual, "derived_linux-and-amd64.gen.go")
+		So(outputVariant("derived.gen.go", "(a || b) && !cgo"), ShouldEqual, "derived_a-or-b-and-not-cgo.gen.go")
+		So(outputVariant("derived.go", "!windows"), ShouldEqual, "derived_not-windows.go")
+
+		So(isOutputVariant("derived.gen.go", "derived_linux.gen.go"), ShouldBeTrue)
+		So(isOutputVariant("derived.gen.go", "derived.gen.go"), ShouldBeFalse)
+		So(isOutputVariant("derived.gen.go", "derived_.gen.go"), ShouldBeFalse)
+		So(isOutputVariant("derived.gen.go", "other_linux.gen.go"), ShouldBeFalse)
+	})
+}
+
+func TestPerFileOutputName(t *testing.T) {
+	Convey("per-file output name", t, func() {
+		So(perFileOutputName("{name}_derived.gen.go", "a/foo.go", false), ShouldEqual, "foo_derived.gen.go")
+		So(perFileOutputName("{name}_derived.gen.go", "a/foo_test.go", true), ShouldEqual, "foo_derived.gen_test.go")
+		So(perFileOutputName("gen_{name}.go", "foo_linux.go", false), ShouldEqual, "gen_foo_linux.go")
+
+		So(isPerFileOutputName("{name}_derived.gen.go", "foo_derived.gen.go"), ShouldBeTrue)
+		So(isPerFileOutputName("{name}_derived.gen.go", "foo_derived.gen_test.go"), ShouldBeTrue)
+		So(isPerFileOutputName("{name}_derived.gen.go", "_derived.gen.go"), ShouldBeFalse)
+		So(isPerFileOutputName("{name}_derived.gen.go", "foo.go"), ShouldBeFalse)
+
+		So(validateOutputPattern("{name}_derived.gen.go"), ShouldBeNil)
+		So(validateOutputPattern("{name}.go"), ShouldNotBeNil)
+		So(validateOutputPattern("derived.gen.go"), ShouldNotBeNil)
+		So(validateOutputPattern("{name}_derived_test.go"), ShouldNotBeNil)
+		So(validateOutputPattern("{name}_{name}.go"), ShouldNotBeNil)
+	})
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"go/ast"
 	"go/doc"
 	"go/parser"
@@ -13,22 +12,48 @@ import (
 )
 
 type TypeInfo struct {
+	// source file path
+	File     string
 	Name     string
 	Assigned string
 	Plugins  *plugin.Entries
 	Ast      ast.Expr
 	Env      plugin.Env
+	// position of the first derive comment
+	Pos  token.Position
+	Fset *token.FileSet
+	// build constraint of the source file, empty if unconstrained
+	Constraint string
+	// whether the source file is a test file
+	Test bool
 }
 
-func ExtractTypes(src []byte) ([]TypeInfo, error) {
+func ExtractTypes(filename string, src []byte) ([]TypeInfo, error) {
 	var types []TypeInfo
 	// parse source code
 	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
-	pkg, _ := ast.NewPackage(fset, map[string]*ast.File{"": file}, nil, nil)
+	// go/doc consumes comments, collect build constraint and doc comments with positions before
+	constraint := fileConstraint(filename, file)
+	typeDocs := make(map[string]*ast.CommentGroup)
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if typeSpec.Doc != nil {
+				typeDocs[typeSpec.Name.Name] = typeSpec.Doc
+			} else {
+				typeDocs[typeSpec.Name.Name] = genDecl.Doc
+			}
+		}
+	}
+	pkg, _ := ast.NewPackage(fset, map[string]*ast.File{filename: file}, nil, nil)
 	d := doc.New(pkg, file.Name.String(), doc.AllDecls)
 
 	env := plugin.MakeEnv(pkg.Name)
@@ -37,21 +62,29 @@ func ExtractTypes(src []byte) ([]TypeInfo, error) {
 		env.Imports.Append(plugin.MakeImportFromAst(i))
 	}
 
-	// select types with 'derive' marker
+	// select types with 'derive' marker, types with invalid comments are skipped
+	var errs utils.ErrorList
 	for _, typ := range d.Types {
 		var typeInfo TypeInfo
+		invalid := false
 
-		cmts := strings.Split(typ.Doc, "\n")
-		for _, cmt := range cmts {
-			dc, err := utils.MatchDeriveComment(cmt)
+		for _, cmt := range deriveComments(fset, typeDocs[typ.Name]) {
+			dc, err := utils.MatchDeriveComment(cmt.Text)
 			if err != nil {
-				return nil, fmt.Errorf("type %s: %v", typ.Name, err)
+				errs.Add(utils.Positioned(err, cmt.Pos, typ.Name, ""))
+				invalid = true
+				continue
 			}
 			if dc == nil {
 				continue
 			}
 			if typeInfo.Name == "" {
+				typeInfo.File = filename
 				typeInfo.Name = typ.Name
+				typeInfo.Pos = cmt.Pos
+				typeInfo.Fset = fset
+				typeInfo.Constraint = constraint
+				typeInfo.Test = strings.HasSuffix(filename, "_test.go")
 				typeInfo.Plugins = plugin.NewEntries(0)
 				spec := typ.Decl.Specs[0].(*ast.TypeSpec)
 				typeInfo.Ast = spec.Name.Obj.Decl.(*ast.TypeSpec).Type
@@ -64,26 +97,68 @@ func ExtractTypes(src []byte) ([]TypeInfo, error) {
 					}
 				}
 			}
-			opts, err := plugin.ParseOptions(dc.OptionsStr)
+			optsPos := cmt.Pos
+			optsPos.Offset += dc.OptionsOffset
+			optsPos.Column += dc.OptionsOffset
+			opts, err := plugin.ParseOptionsAt(dc.OptionsStr, optsPos)
 			if err != nil {
-				return nil, fmt.Errorf("type %s: %v", typ.Name, err)
+				errs.Add(utils.Positioned(err, cmt.Pos, typ.Name, dc.Plugin))
+				invalid = true
+				continue
 			}
 
 			// merge options
 			idx := typeInfo.Plugins.FindBy(func(e plugin.Entry) bool { return e.Plugin == dc.Plugin })
 			if idx == -1 {
-				typeInfo.Plugins.Append(plugin.MakeEntry(dc.Plugin, opts))
+				entry := plugin.MakeEntry(dc.Plugin, opts)
+				entry.Pos = cmt.Pos
+				typeInfo.Plugins.Append(entry)
 			} else {
 				err := typeInfo.Plugins.Index(idx).Opts.Merge(opts)
 				if err != nil {
-					return nil, fmt.Errorf("type %s: %v", typ.Name, err)
+					errs.Add(utils.Positioned(err, cmt.Pos, typ.Name, dc.Plugin))
+					invalid = true
 				}
 			}
 		}
-		if typeInfo.Name != "" {
+		if typeInfo.Name != "" && !invalid {
 			typeInfo.Env = env
 			types = append(types, typeInfo)
 		}
 	}
-	return types, nil
+	return types, errs.Err()
+}
+
+type commentLine struct {
+	Text string
+	Pos  token.Position
+}
+
+// deriveComments splits comments into lines with their positions, comment markers are removed.
+func deriveComments(fset *token.FileSet, group *ast.CommentGroup) []commentLine {
+	if group == nil {
+		return nil
+	}
+	var lines []commentLine
+	for _, cmt := range group.List {
+		pos := fset.Position(cmt.Slash)
+		text := cmt.Text
+		if !strings.HasPrefix(text, "/*") {
+			lines = append(lines, commentLine{Text: text, Pos: pos})
+			continue
+		}
+		// block comment
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+		pos.Offset += len("/*")
+		pos.Column += len("/*")
+		for idx, line := range strings.Split(text, "\n") {
+			if idx > 0 {
+				pos.Offset += len(lines[len(lines)-1].Text) + 1
+				pos.Line++
+				pos.Column = 1
+			}
+			lines = append(lines, commentLine{Text: line, Pos: pos})
+		}
+	}
+	return lines
 }
@@ -2,6 +2,7 @@ package access
 
 import (
 	"go/ast"
+	"go/token"
 	"io"
 	"strings"
 
@@ -66,16 +67,26 @@ func (a Access) GenerateTo(w io.Writer, env plugin.Env, typeInfo plugin.TypeInfo
 		// field options parse
 		var fieldOpts = plugin.NewOptions()
 		for _, cmt := range cmtList {
+			var pos token.Position
+			if typeInfo.Fset != nil {
+				pos = typeInfo.Fset.Position(cmt.Slash)
+			}
 			dc, err := utils.MatchPluginComment(cmt.Text)
 			if err != nil {
-				return pre, err
+				return pre, utils.Positioned(err, pos, "", "")
+			}
+			if dc == nil {
+				continue
 			}
-			opts, err := plugin.ParseOptions(dc.OptionsStr)
+			optsPos := pos
+			optsPos.Offset += dc.OptionsOffset
+			optsPos.Column += dc.OptionsOffset
+			opts, err := plugin.ParseOptionsAt(dc.OptionsStr, optsPos)
 			if err != nil {
-				return pre, err
+				return pre, utils.Positioned(err, pos, "", "")
 			}
 			if err = fieldOpts.Merge(opts); err != nil {
-				return pre, err
+				return pre, utils.Positioned(err, pos, "", "")
 			}
 		}
 
@@ -9,209 +9,280 @@ import (
 	"sort"
 )
 
-type Entries struct {
-	elements []Entry
+type ValueSet struct {
+	elements        map[Value]uint32
+	elementSequence []Value
 }
 
-func NewEntries(capacity int) *Entries {
-	return &Entries{
-		elements: make([]Entry, 0, capacity),
+func NewValueSet(capacity int) *ValueSet {
+	set := new(ValueSet)
+	if capacity > 0 {
+		set.elements = make(map[Value]uint32, capacity)
+		set.elementSequence = make([]Value, 0, capacity)
+	} else {
+		set.elements = make(map[Value]uint32)
 	}
+	return set
 }
 
-func NewEntriesFromSlice(slice []Entry) *Entries {
-	return &Entries{
-		elements: slice,
+func NewValueSetFromSlice(items []Value) *ValueSet {
+	set := NewValueSet(len(items))
+	for _, item := range items {
+		set.Append(item)
 	}
+	return set
 }
 
-func (s *Entries) Len() int {
-	if s == nil {
+func (set *ValueSet) Len() int {
+	if set == nil {
 		return 0
 	}
-	return len(s.elements)
-}
-
-func (s *Entries) IsEmpty() bool {
-	return s.Len() == 0
+	return len(set.elements)
 }
 
-func (s *Entries) Append(items ...Entry) {
-	s.elements = append(s.elements, items...)
+func (set *ValueSet) IsEmpty() bool {
+	return set.Len() == 0
 }
 
-func (s *Entries) Clone() *Entries {
-	cloned := &Entries{
-		elements: make([]Entry, s.Len()),
+func (set *ValueSet) ToSlice() []Value {
+	if set == nil {
+		return nil
 	}
-	copy(cloned.elements, s.elements)
-	return cloned
+	s := make([]Value, set.Len())
+	copy(s, set.elementSequence)
+	return s
 }
 
-func (s *Entries) ToSlice() []Entry {
-	slice := make([]Entry, s.Len())
-	copy(slice, s.elements)
-	return slice
+// NOTICE: efficient but unsafe
+func (set *ValueSet) ToSliceRef() []Value {
+	return set.elementSequence
 }
 
-func (s *Entries) ToSliceRef() []Entry {
-	return s.elements
+func (set *ValueSet) Append(keys ...Value) {
+	for _, key := range keys {
+		if _, ok := set.elements[key]; !ok {
+			set.elements[key] = uint32(len(set.elementSequence))
+			set.elementSequence = append(set.elementSequence, key)
+		}
+	}
 }
 
-func (s *Entries) Clear() {
-	s.elements = s.elements[:0]
+func (set *ValueSet) Clear() {
+	set.elements = make(map[Value]uint32)
+	set.elementSequence = set.elementSequence[:0]
 }
 
-func (s *Entries) Insert(idx int, items ...Entry) {
-	if idx < 0 {
-		idx += s.Len()
-	}
-	if l := len(s.elements) + len(items); l > cap(s.elements) {
-		// reallocate
-		result := make([]Entry, l)
-		copy(result, s.elements[:idx])
-		copy(result[idx:], items)
-		copy(result[idx+len(items):], s.elements[idx:])
-		s.elements = result
-		return
+func (set *ValueSet) Clone() *ValueSet {
+	cloned := NewValueSet(set.Len())
+	for idx, item := range set.elementSequence {
+		cloned.elements[item] = uint32(idx)
+		cloned.elementSequence = append(cloned.elementSequence, item)
 	}
+	return cloned
+}
 
-	l := s.Len()
-	s.elements = append(s.elements, items...)
-	copy(s.elements[idx+len(items):], s.elements[idx:l])
-	copy(s.elements[idx:], items)
+func (set *ValueSet) Difference(another *ValueSet) *ValueSet {
+	difference := NewValueSet(0)
+	set.ForEach(func(item Value) {
+		if !another.Contains(item) {
+			difference.Append(item)
+		}
+	})
+	return difference
 }
 
-func (s *Entries) Remove(idx int) {
-	if idx < 0 {
-		idx += s.Len()
+func (set *ValueSet) Equal(another *ValueSet) bool {
+	if set.Len() != another.Len() {
+		return false
 	}
-	s.elements = append(s.elements[:idx], s.elements[idx+1:]...)
+	return set.ContainsAll(another.elementSequence...)
 }
 
-func (s *Entries) RemoveRange(from, to int) {
-	if from < 0 {
-		from += s.Len()
-	}
-	if to < 0 {
-		to += s.Len()
+// TODO keep order
+func (set *ValueSet) Intersect(another *ValueSet) *ValueSet {
+	intersection := NewValueSet(0)
+	if set.Len() < another.Len() {
+		for item := range set.elements {
+			if another.Contains(item) {
+				intersection.Append(item)
+			}
+		}
+	} else {
+		for item := range another.elements {
+			if set.Contains(item) {
+				intersection.Append(item)
+			}
+		}
 	}
-	s.elements = append(s.elements[:from], s.elements[to+1:]...)
+	return intersection
 }
 
-func (s *Entries) RemoveFrom(idx int) {
-	if idx < 0 {
-		idx += s.Len()
-	}
-	s.elements = s.elements[:idx]
+func (set *ValueSet) Union(another *ValueSet) *ValueSet {
+	union := set.Clone()
+	union.InPlaceUnion(another)
+	return union
 }
 
-func (s *Entries) RemoveTo(idx int) {
-	if idx < 0 {
-		idx += s.Len()
-	}
-	s.elements = s.elements[idx+1:]
+func (set *ValueSet) InPlaceUnion(another *ValueSet) {
+	another.ForEach(func(item Value) {
+		set.Append(item)
+	})
 }
 
-func (s *Entries) Concat(another *Entries) *Entries {
-	result := s.Clone()
-	if another.IsEmpty() {
-		return result
-	}
-	result.Append(another.elements...)
-	return result
+func (set *ValueSet) IsProperSubsetOf(another *ValueSet) bool {
+	return !set.Equal(another) && set.IsSubsetOf(another)
 }
 
-func (s *Entries) InPlaceConcat(another *Entries) {
-	if another.IsEmpty() {
-		return
+func (set *ValueSet) IsProperSupersetOf(another *ValueSet) bool {
+	return !set.Equal(another) && set.IsSupersetOf(another)
+}
+
+func (set *ValueSet) IsSubsetOf(another *ValueSet) bool {
+	if set.Len() > another.Len() {
+		return false
 	}
-	s.Append(another.elements...)
+	for item := range set.elements {
+		if !another.Contains(item) {
+			return false
+		}
+	}
+	return true
 }
 
-func (s *Entries) ForEach(f func(Entry)) {
-	if s.IsEmpty() {
+func (set *ValueSet) IsSupersetOf(another *ValueSet) bool {
+	return another.IsSubsetOf(set)
+}
+
+func (set *ValueSet) ForEach(f func(Value)) {
+	if set.IsEmpty() {
 		return
 	}
-	for _, item := range s.elements {
+	for _, item := range set.elementSequence {
 		f(item)
 	}
 }
 
-func (s *Entries) ForEachWithIndex(f func(int, Entry)) {
-	if s.IsEmpty() {
+func (set *ValueSet) ForEachWithIndex(f func(int, Value)) {
+	if set.IsEmpty() {
 		return
 	}
-	for idx, item := range s.elements {
+	for idx, item := range set.elementSequence {
 		f(idx, item)
 	}
 }
 
-func (s *Entries) Filter(f func(Entry) bool) *Entries {
-	result := NewEntries(0)
-	for _, item := range s.elements {
+func (set *ValueSet) Filter(f func(Value) bool) *ValueSet {
+	result := NewValueSet(0)
+	set.ForEach(func(item Value) {
 		if f(item) {
 			result.Append(item)
 		}
-	}
+	})
 	return result
 }
 
-func (s *Entries) Index(idx int) *Entry {
-	if idx < 0 {
-		idx += s.Len()
+func (set *ValueSet) Remove(key Value) {
+	if idx, ok := set.elements[key]; ok {
+		l := set.Len()
+		delete(set.elements, key)
+		for ; idx < uint32(l-1); idx++ {
+			item := set.elementSequence[idx+1]
+			set.elementSequence[idx] = item
+			set.elements[item] = idx
+		}
+		set.elementSequence = set.elementSequence[:l-1]
 	}
-	return &s.elements[idx]
 }
 
-func (s *Entries) IndexRange(from, to int) *Entries {
-	if from < 0 {
-		from += s.Len()
+func (set *ValueSet) Contains(key Value) bool {
+	_, ok := set.elements[key]
+	return ok
+}
+
+func (set *ValueSet) ContainsAny(keys ...Value) bool {
+	for _, key := range keys {
+		if set.Contains(key) {
+			return true
+		}
 	}
-	if to < 0 {
-		to += s.Len()
+	return false
+}
+
+func (set *ValueSet) ContainsAll(keys ...Value) bool {
+	for _, key := range keys {
+		if !set.Contains(key) {
+			return false
+		}
 	}
-	return NewEntriesFromSlice(s.elements[from:to])
+	return true
 }
 
-func (s *Entries) IndexFrom(idx int) *Entries {
-	if idx < 0 {
-		idx += s.Len()
+func (set *ValueSet) DoUntil(f func(Value) bool) int {
+	for idx, item := range set.elementSequence {
+		if f(item) {
+			return idx
+		}
 	}
-	return NewEntriesFromSlice(s.elements[idx:])
+	return -1
 }
 
-func (s *Entries) IndexTo(idx int) *Entries {
-	if idx < 0 {
-		idx += s.Len()
+func (set *ValueSet) DoWhile(f func(Value) bool) int {
+	for idx, item := range set.elementSequence {
+		if !f(item) {
+			return idx
+		}
 	}
-	return NewEntriesFromSlice(s.elements[:idx])
+	return -1
 }
 
-func (s *Entries) FindBy(f func(Entry) bool) int {
-	if s.IsEmpty() {
-		return -1
+func (set *ValueSet) DoUntilError(f func(Value) error) error {
+	for _, item := range set.elementSequence {
+		if err := f(item); err != nil {
+			return err
+		}
 	}
-	for idx, n := range s.elements {
-		if f(n) {
-			return idx
+	return nil
+}
+
+func (set *ValueSet) All(f func(Value) bool) bool {
+	for item := range set.elements {
+		if !f(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *ValueSet) Any(f func(Value) bool) bool {
+	for item := range set.elements {
+		if f(item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *ValueSet) FindBy(f func(Value) bool) *Value {
+	for _, item := range set.elementSequence {
+		if f(item) {
+			return &item
 		}
 	}
-	return -1
+	return nil
 }
 
-func (s *Entries) FindLastBy(f func(Entry) bool) int {
-	for idx := s.Len() - 1; idx >= 0; idx-- {
-		if f(s.elements[idx]) {
-			return idx
+func (set *ValueSet) FindLastBy(f func(Value) bool) *Value {
+	for i := set.Len() - 1; i >= 0; i-- {
+		if item := set.elementSequence[i]; f(item) {
+			return &item
 		}
 	}
-	return -1
+	return nil
 }
 
-func (s *Entries) CountBy(f func(Entry) bool) uint {
-	count := uint(0)
-	s.ForEach(func(item Entry) {
+func (set *ValueSet) CountBy(f func(Value) bool) int {
+	count := 0
+	set.ForEach(func(item Value) {
 		if f(item) {
 			count++
 		}
@@ -219,9 +290,9 @@ func (s *Entries) CountBy(f func(Entry) bool) uint {
 	return count
 }
 
-func (s *Entries) GroupByBool(f func(Entry) bool) (trueGroup, falseGroup *Entries) {
-	trueGroup, falseGroup = NewEntries(0), NewEntries(0)
-	s.ForEach(func(item Entry) {
+func (set *ValueSet) GroupByBool(f func(Value) bool) (trueGroup *ValueSet, falseGroup *ValueSet) {
+	trueGroup, falseGroup = NewValueSet(0), NewValueSet(0)
+	set.ForEach(func(item Value) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -231,13 +302,13 @@ func (s *Entries) GroupByBool(f func(Entry) bool) (trueGroup, falseGroup *Entrie
 	return trueGroup, falseGroup
 }
 
-func (s Entries) GroupByStr(f func(Entry) string) map[string]*Entries {
-	groups := make(map[string]*Entries)
-	s.ForEach(func(item Entry) {
+func (set *ValueSet) GroupByStr(f func(Value) string) map[string]*ValueSet {
+	groups := make(map[string]*ValueSet)
+	set.ForEach(func(item Value) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewEntries(0)
+			group = NewValueSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -245,13 +316,13 @@ func (s Entries) GroupByStr(f func(Entry) string) map[string]*Entries {
 	return groups
 }
 
-func (s Entries) GroupByInt(f func(Entry) int) map[int]*Entries {
-	groups := make(map[int]*Entries)
-	s.ForEach(func(item Entry) {
+func (set *ValueSet) GroupByInt(f func(Value) int) map[int]*ValueSet {
+	groups := make(map[int]*ValueSet)
+	set.ForEach(func(item Value) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewEntries(0)
+			group = NewValueSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -259,13 +330,13 @@ func (s Entries) GroupByInt(f func(Entry) int) map[int]*Entries {
 	return groups
 }
 
-func (s *Entries) GroupBy(f func(Entry) interface{}) map[interface{}]*Entries {
-	groups := make(map[interface{}]*Entries)
-	s.ForEach(func(item Entry) {
+func (set *ValueSet) GroupBy(f func(Value) interface{}) map[interface{}]*ValueSet {
+	groups := make(map[interface{}]*ValueSet)
+	set.ForEach(func(item Value) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewEntries(0)
+			group = NewValueSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -273,10 +344,10 @@ func (s *Entries) GroupBy(f func(Entry) interface{}) map[interface{}]*Entries {
 	return groups
 }
 
-// f: func(Entry) T
+// f: func(Value) T
 // return: []T
-func (s *Entries) Map(f interface{}) interface{} {
-	expected := "f should be func(Entry)T"
+func (set *ValueSet) Map(f interface{}) interface{} {
+	expected := "f should be func(Value)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -285,7 +356,7 @@ func (s *Entries) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(Entry)).Elem()
+	elemType := reflect.TypeOf(new(Value)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -293,19 +364,19 @@ func (s *Entries) Map(f interface{}) interface{} {
 		panic(expected)
 	}
 	outType := ft.Out(0)
-	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
-	s.ForEach(func(item Entry) {
+	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
+	set.ForEach(func(item Value) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(Entry) *T
-//    func(Entry) (T, bool)
-//    func(Entry) (T, error)
+// f: func(Value) *T
+//    func(Value) (T, bool)
+//    func(Value) (T, error)
 // return: []T
-func (s *Entries) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(Entry) *T / func(Entry) (T, bool) / func(Entry) (T, error)"
+func (set *ValueSet) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(Value) *T / func(Value) (T, bool) / func(Value) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -315,13 +386,13 @@ func (s *Entries) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(Entry)).Elem() {
+	if in != reflect.TypeOf(new(Value)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(Entry) *T
+		// func(Value) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -338,7 +409,7 @@ func (s *Entries) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(Entry) (T, bool)
+			// func(Value) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -346,7 +417,7 @@ func (s *Entries) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(Entry) (T, error)
+			// func(Value) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -360,8 +431,8 @@ func (s *Entries) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 
-	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
-	s.ForEach(func(item Entry) {
+	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
+	set.ForEach(func(item Value) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -370,162 +441,111 @@ func (s *Entries) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (s *Entries) DoUntil(f func(Entry) bool) int {
-	for idx, item := range s.elements {
-		if f(item) {
-			return idx
-		}
-	}
-	return -1
-}
-
-func (s *Entries) DoWhile(f func(Entry) bool) int {
-	for idx, item := range s.elements {
-		if !f(item) {
-			return idx
-		}
-	}
-	return -1
-}
-
-func (s *Entries) DoUntilError(f func(Entry) error) error {
-	for _, item := range s.elements {
-		if err := f(item); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (s *Entries) All(f func(Entry) bool) bool {
-	for _, item := range s.elements {
-		if !f(item) {
-			return false
-		}
-	}
-	return true
-}
-
-func (s *Entries) Any(f func(Entry) bool) bool {
-	for _, item := range s.elements {
-		if f(item) {
-			return true
-		}
-	}
-	return false
-}
-
-func (s *Entries) Reduce(f func(Entry, Entry) Entry) Entry {
-	if s.IsEmpty() {
-		var defaultVal Entry
+func (set *ValueSet) Reduce(f func(Value, Value) Value) Value {
+	if set.IsEmpty() {
+		var defaultVal Value
 		return defaultVal
 	}
-	ret := s.elements[0]
-	for _, item := range s.elements[1:] {
+	ret := set.elementSequence[0]
+	for _, item := range set.elementSequence[1:] {
 		ret = f(ret, item)
 	}
 	return ret
 }
 
-func (s *Entries) Fold(init Entry, f func(Entry, Entry) Entry) Entry {
-	if s.IsEmpty() {
+func (set *ValueSet) Fold(init Value, f func(Value, Value) Value) Value {
+	if set.IsEmpty() {
 		return init
 	}
-	for _, item := range s.elements {
+	for _, item := range set.elementSequence {
 		init = f(init, item)
 	}
 	return init
 }
 
-func (s *Entries) String() string {
-	return fmt.Sprint(s.elements)
+func (set *ValueSet) String() string {
+	return fmt.Sprint(set.elementSequence)
 }
 
-func (s Entries) MarshalJSON() ([]byte, error) {
-	return json.Marshal(s.elements)
+func (set ValueSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.ToSlice())
 }
 
-func (s *Entries) UnmarshalJSON(b []byte) error {
-	return json.Unmarshal(b, &s.elements)
+func (set *ValueSet) UnmarshalJSON(b []byte) error {
+	s := make([]Value, 0)
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+	*set = *NewValueSetFromSlice(s)
+	return nil
 }
 
-type ImportSet struct {
-	cmp             func(i, j Import) bool
-	elements        map[Import]uint32
-	elementSequence []Import
+type PluginSet struct {
+	elements        map[Plugin]uint32
+	elementSequence []Plugin
 }
 
-func NewImportSet(capacity int, cmp func(i, j Import) bool) *ImportSet {
-	set := new(ImportSet)
+func NewPluginSet(capacity int) *PluginSet {
+	set := new(PluginSet)
 	if capacity > 0 {
-		set.elements = make(map[Import]uint32, capacity)
-		set.elementSequence = make([]Import, 0, capacity)
+		set.elements = make(map[Plugin]uint32, capacity)
+		set.elementSequence = make([]Plugin, 0, capacity)
 	} else {
-		set.elements = make(map[Import]uint32)
+		set.elements = make(map[Plugin]uint32)
 	}
-	set.cmp = cmp
 	return set
 }
 
-func NewImportSetFromSlice(items []Import, cmp func(i, j Import) bool) *ImportSet {
-	set := NewImportSet(len(items), cmp)
+func NewPluginSetFromSlice(items []Plugin) *PluginSet {
+	set := NewPluginSet(len(items))
 	for _, item := range items {
 		set.Append(item)
 	}
 	return set
 }
 
-func (set *ImportSet) Len() int {
+func (set *PluginSet) Len() int {
 	if set == nil {
 		return 0
 	}
 	return len(set.elements)
 }
 
-func (set *ImportSet) IsEmpty() bool {
+func (set *PluginSet) IsEmpty() bool {
 	return set.Len() == 0
 }
 
-func (set *ImportSet) ToSlice() []Import {
+func (set *PluginSet) ToSlice() []Plugin {
 	if set == nil {
 		return nil
 	}
-	s := make([]Import, set.Len())
+	s := make([]Plugin, set.Len())
 	copy(s, set.elementSequence)
 	return s
 }
 
 // NOTICE: efficient but unsafe
-func (set *ImportSet) ToSliceRef() []Import {
+func (set *PluginSet) ToSliceRef() []Plugin {
 	return set.elementSequence
 }
 
-func (set *ImportSet) Append(keys ...Import) {
+func (set *PluginSet) Append(keys ...Plugin) {
 	for _, key := range keys {
 		if _, ok := set.elements[key]; !ok {
-			idx := sort.Search(len(set.elementSequence), func(i int) bool {
-				return set.cmp(key, set.elementSequence[i])
-			})
-			l := len(set.elementSequence)
+			set.elements[key] = uint32(len(set.elementSequence))
 			set.elementSequence = append(set.elementSequence, key)
-			for i := l; i > idx; i-- {
-				set.elements[set.elementSequence[i]] = uint32(i + 1)
-				set.elementSequence[i] = set.elementSequence[i-1]
-			}
-			set.elements[set.elementSequence[idx]] = uint32(idx + 1)
-			set.elementSequence[idx] = key
-			set.elements[key] = uint32(idx)
 		}
 	}
 }
 
-func (set *ImportSet) Clear() {
-	set.elements = make(map[Import]uint32)
+func (set *PluginSet) Clear() {
+	set.elements = make(map[Plugin]uint32)
 	set.elementSequence = set.elementSequence[:0]
 }
 
-func (set *ImportSet) Clone() *ImportSet {
-	cloned := NewImportSet(set.Len(), set.cmp)
+func (set *PluginSet) Clone() *PluginSet {
+	cloned := NewPluginSet(set.Len())
 	for idx, item := range set.elementSequence {
 		cloned.elements[item] = uint32(idx)
 		cloned.elementSequence = append(cloned.elementSequence, item)
@@ -533,9 +553,9 @@ func (set *ImportSet) Clone() *ImportSet {
 	return cloned
 }
 
-func (set *ImportSet) Difference(another *ImportSet) *ImportSet {
-	difference := NewImportSet(0, set.cmp)
-	set.ForEach(func(item Import) {
+func (set *PluginSet) Difference(another *PluginSet) *PluginSet {
+	difference := NewPluginSet(0)
+	set.ForEach(func(item Plugin) {
 		if !another.Contains(item) {
 			difference.Append(item)
 		}
@@ -543,15 +563,16 @@ func (set *ImportSet) Difference(another *ImportSet) *ImportSet {
 	return difference
 }
 
-func (set *ImportSet) Equal(another *ImportSet) bool {
+func (set *PluginSet) Equal(another *PluginSet) bool {
 	if set.Len() != another.Len() {
 		return false
 	}
 	return set.ContainsAll(another.elementSequence...)
 }
 
-func (set *ImportSet) Intersect(another *ImportSet) *ImportSet {
-	intersection := NewImportSet(0, set.cmp)
+// TODO keep order
+func (set *PluginSet) Intersect(another *PluginSet) *PluginSet {
+	intersection := NewPluginSet(0)
 	if set.Len() < another.Len() {
 		for item := range set.elements {
 			if another.Contains(item) {
@@ -568,27 +589,27 @@ func (set *ImportSet) Intersect(another *ImportSet) *ImportSet {
 	return intersection
 }
 
-func (set *ImportSet) Union(another *ImportSet) *ImportSet {
+func (set *PluginSet) Union(another *PluginSet) *PluginSet {
 	union := set.Clone()
 	union.InPlaceUnion(another)
 	return union
 }
 
-func (set *ImportSet) InPlaceUnion(another *ImportSet) {
-	another.ForEach(func(item Import) {
+func (set *PluginSet) InPlaceUnion(another *PluginSet) {
+	another.ForEach(func(item Plugin) {
 		set.Append(item)
 	})
 }
 
-func (set *ImportSet) IsProperSubsetOf(another *ImportSet) bool {
+func (set *PluginSet) IsProperSubsetOf(another *PluginSet) bool {
 	return !set.Equal(another) && set.IsSubsetOf(another)
 }
 
-func (set *ImportSet) IsProperSupersetOf(another *ImportSet) bool {
+func (set *PluginSet) IsProperSupersetOf(another *PluginSet) bool {
 	return !set.Equal(another) && set.IsSupersetOf(another)
 }
 
-func (set *ImportSet) IsSubsetOf(another *ImportSet) bool {
+func (set *PluginSet) IsSubsetOf(another *PluginSet) bool {
 	if set.Len() > another.Len() {
 		return false
 	}
@@ -600,11 +621,11 @@ func (set *ImportSet) IsSubsetOf(another *ImportSet) bool {
 	return true
 }
 
-func (set *ImportSet) IsSupersetOf(another *ImportSet) bool {
+func (set *PluginSet) IsSupersetOf(another *PluginSet) bool {
 	return another.IsSubsetOf(set)
 }
 
-func (set *ImportSet) ForEach(f func(Import)) {
+func (set *PluginSet) ForEach(f func(Plugin)) {
 	if set.IsEmpty() {
 		return
 	}
@@ -613,7 +634,7 @@ func (set *ImportSet) ForEach(f func(Import)) {
 	}
 }
 
-func (set *ImportSet) ForEachWithIndex(f func(int, Import)) {
+func (set *PluginSet) ForEachWithIndex(f func(int, Plugin)) {
 	if set.IsEmpty() {
 		return
 	}
@@ -622,9 +643,9 @@ func (set *ImportSet) ForEachWithIndex(f func(int, Import)) {
 	}
 }
 
-func (set *ImportSet) Filter(f func(Import) bool) *ImportSet {
-	result := NewImportSet(0, set.cmp)
-	set.ForEach(func(item Import) {
+func (set *PluginSet) Filter(f func(Plugin) bool) *PluginSet {
+	result := NewPluginSet(0)
+	set.ForEach(func(item Plugin) {
 		if f(item) {
 			result.Append(item)
 		}
@@ -632,7 +653,7 @@ func (set *ImportSet) Filter(f func(Import) bool) *ImportSet {
 	return result
 }
 
-func (set *ImportSet) Remove(key Import) {
+func (set *PluginSet) Remove(key Plugin) {
 	if idx, ok := set.elements[key]; ok {
 		l := set.Len()
 		delete(set.elements, key)
@@ -645,12 +666,12 @@ func (set *ImportSet) Remove(key Import) {
 	}
 }
 
-func (set *ImportSet) Contains(key Import) bool {
+func (set *PluginSet) Contains(key Plugin) bool {
 	_, ok := set.elements[key]
 	return ok
 }
 
-func (set *ImportSet) ContainsAny(keys ...Import) bool {
+func (set *PluginSet) ContainsAny(keys ...Plugin) bool {
 	for _, key := range keys {
 		if set.Contains(key) {
 			return true
@@ -659,7 +680,7 @@ func (set *ImportSet) ContainsAny(keys ...Import) bool {
 	return false
 }
 
-func (set *ImportSet) ContainsAll(keys ...Import) bool {
+func (set *PluginSet) ContainsAll(keys ...Plugin) bool {
 	for _, key := range keys {
 		if !set.Contains(key) {
 			return false
@@ -668,7 +689,7 @@ func (set *ImportSet) ContainsAll(keys ...Import) bool {
 	return true
 }
 
-func (set *ImportSet) DoUntil(f func(Import) bool) int {
+func (set *PluginSet) DoUntil(f func(Plugin) bool) int {
 	for idx, item := range set.elementSequence {
 		if f(item) {
 			return idx
@@ -677,7 +698,7 @@ func (set *ImportSet) DoUntil(f func(Import) bool) int {
 	return -1
 }
 
-func (set *ImportSet) DoWhile(f func(Import) bool) int {
+func (set *PluginSet) DoWhile(f func(Plugin) bool) int {
 	for idx, item := range set.elementSequence {
 		if !f(item) {
 			return idx
@@ -686,7 +707,7 @@ func (set *ImportSet) DoWhile(f func(Import) bool) int {
 	return -1
 }
 
-func (set *ImportSet) DoUntilError(f func(Import) error) error {
+func (set *PluginSet) DoUntilError(f func(Plugin) error) error {
 	for _, item := range set.elementSequence {
 		if err := f(item); err != nil {
 			return err
@@ -695,7 +716,7 @@ func (set *ImportSet) DoUntilError(f func(Import) error) error {
 	return nil
 }
 
-func (set *ImportSet) All(f func(Import) bool) bool {
+func (set *PluginSet) All(f func(Plugin) bool) bool {
 	for item := range set.elements {
 		if !f(item) {
 			return false
@@ -704,7 +725,7 @@ func (set *ImportSet) All(f func(Import) bool) bool {
 	return true
 }
 
-func (set *ImportSet) Any(f func(Import) bool) bool {
+func (set *PluginSet) Any(f func(Plugin) bool) bool {
 	for item := range set.elements {
 		if f(item) {
 			return true
@@ -713,7 +734,7 @@ func (set *ImportSet) Any(f func(Import) bool) bool {
 	return false
 }
 
-func (set *ImportSet) FindBy(f func(Import) bool) *Import {
+func (set *PluginSet) FindBy(f func(Plugin) bool) *Plugin {
 	for _, item := range set.elementSequence {
 		if f(item) {
 			return &item
@@ -722,7 +743,7 @@ func (set *ImportSet) FindBy(f func(Import) bool) *Import {
 	return nil
 }
 
-func (set *ImportSet) FindLastBy(f func(Import) bool) *Import {
+func (set *PluginSet) FindLastBy(f func(Plugin) bool) *Plugin {
 	for i := set.Len() - 1; i >= 0; i-- {
 		if item := set.elementSequence[i]; f(item) {
 			return &item
@@ -731,9 +752,9 @@ func (set *ImportSet) FindLastBy(f func(Import) bool) *Import {
 	return nil
 }
 
-func (set *ImportSet) CountBy(f func(Import) bool) int {
+func (set *PluginSet) CountBy(f func(Plugin) bool) int {
 	count := 0
-	set.ForEach(func(item Import) {
+	set.ForEach(func(item Plugin) {
 		if f(item) {
 			count++
 		}
@@ -741,9 +762,9 @@ func (set *ImportSet) CountBy(f func(Import) bool) int {
 	return count
 }
 
-func (set *ImportSet) GroupByBool(f func(Import) bool) (trueGroup *ImportSet, falseGroup *ImportSet) {
-	trueGroup, falseGroup = NewImportSet(0, set.cmp), NewImportSet(0, set.cmp)
-	set.ForEach(func(item Import) {
+func (set *PluginSet) GroupByBool(f func(Plugin) bool) (trueGroup *PluginSet, falseGroup *PluginSet) {
+	trueGroup, falseGroup = NewPluginSet(0), NewPluginSet(0)
+	set.ForEach(func(item Plugin) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -753,13 +774,13 @@ func (set *ImportSet) GroupByBool(f func(Import) bool) (trueGroup *ImportSet, fa
 	return trueGroup, falseGroup
 }
 
-func (set *ImportSet) GroupByStr(f func(Import) string) map[string]*ImportSet {
-	groups := make(map[string]*ImportSet)
-	set.ForEach(func(item Import) {
+func (set *PluginSet) GroupByStr(f func(Plugin) string) map[string]*PluginSet {
+	groups := make(map[string]*PluginSet)
+	set.ForEach(func(item Plugin) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewImportSet(0, set.cmp)
+			group = NewPluginSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -767,13 +788,13 @@ func (set *ImportSet) GroupByStr(f func(Import) string) map[string]*ImportSet {
 	return groups
 }
 
-func (set *ImportSet) GroupByInt(f func(Import) int) map[int]*ImportSet {
-	groups := make(map[int]*ImportSet)
-	set.ForEach(func(item Import) {
+func (set *PluginSet) GroupByInt(f func(Plugin) int) map[int]*PluginSet {
+	groups := make(map[int]*PluginSet)
+	set.ForEach(func(item Plugin) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewImportSet(0, set.cmp)
+			group = NewPluginSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -781,13 +802,13 @@ func (set *ImportSet) GroupByInt(f func(Import) int) map[int]*ImportSet {
 	return groups
 }
 
-func (set *ImportSet) GroupBy(f func(Import) interface{}) map[interface{}]*ImportSet {
-	groups := make(map[interface{}]*ImportSet)
-	set.ForEach(func(item Import) {
+func (set *PluginSet) GroupBy(f func(Plugin) interface{}) map[interface{}]*PluginSet {
+	groups := make(map[interface{}]*PluginSet)
+	set.ForEach(func(item Plugin) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewImportSet(0, set.cmp)
+			group = NewPluginSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -795,10 +816,10 @@ func (set *ImportSet) GroupBy(f func(Import) interface{}) map[interface{}]*Impor
 	return groups
 }
 
-// f: func(Import) T
+// f: func(Plugin) T
 // return: []T
-func (set *ImportSet) Map(f interface{}) interface{} {
-	expected := "f should be func(Import)T"
+func (set *PluginSet) Map(f interface{}) interface{} {
+	expected := "f should be func(Plugin)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -807,7 +828,7 @@ func (set *ImportSet) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(Import)).Elem()
+	elemType := reflect.TypeOf(new(Plugin)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -816,18 +837,18 @@ func (set *ImportSet) Map(f interface{}) interface{} {
 	}
 	outType := ft.Out(0)
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item Import) {
+	set.ForEach(func(item Plugin) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(Import) *T
-//    func(Import) (T, bool)
-//    func(Import) (T, error)
+// f: func(Plugin) *T
+//    func(Plugin) (T, bool)
+//    func(Plugin) (T, error)
 // return: []T
-func (set *ImportSet) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(Import) *T / func(Import) (T, bool) / func(Import) (T, error)"
+func (set *PluginSet) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(Plugin) *T / func(Plugin) (T, bool) / func(Plugin) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -837,13 +858,13 @@ func (set *ImportSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(Import)).Elem() {
+	if in != reflect.TypeOf(new(Plugin)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(Import) *T
+		// func(Plugin) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -860,7 +881,7 @@ func (set *ImportSet) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(Import) (T, bool)
+			// func(Plugin) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -868,7 +889,7 @@ func (set *ImportSet) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(Import) (T, error)
+			// func(Plugin) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -883,7 +904,7 @@ func (set *ImportSet) FilterMap(f interface{}) interface{} {
 	}
 
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item Import) {
+	set.ForEach(func(item Plugin) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -892,9 +913,9 @@ func (set *ImportSet) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (set *ImportSet) Reduce(f func(Import, Import) Import) Import {
+func (set *PluginSet) Reduce(f func(Plugin, Plugin) Plugin) Plugin {
 	if set.IsEmpty() {
-		var defaultVal Import
+		var defaultVal Plugin
 		return defaultVal
 	}
 	ret := set.elementSequence[0]
@@ -904,7 +925,7 @@ func (set *ImportSet) Reduce(f func(Import, Import) Import) Import {
 	return ret
 }
 
-func (set *ImportSet) Fold(init Import, f func(Import, Import) Import) Import {
+func (set *PluginSet) Fold(init Plugin, f func(Plugin, Plugin) Plugin) Plugin {
 	if set.IsEmpty() {
 		return init
 	}
@@ -914,292 +935,227 @@ func (set *ImportSet) Fold(init Import, f func(Import, Import) Import) Import {
 	return init
 }
 
-func (set *ImportSet) String() string {
+func (set *PluginSet) String() string {
 	return fmt.Sprint(set.elementSequence)
 }
 
-func (set ImportSet) MarshalJSON() ([]byte, error) {
+func (set PluginSet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(set.ToSlice())
 }
 
-func (set *ImportSet) UnmarshalJSON(b []byte) error {
-	return fmt.Errorf("unsupported")
+func (set *PluginSet) UnmarshalJSON(b []byte) error {
+	s := make([]Plugin, 0)
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+	*set = *NewPluginSetFromSlice(s)
+	return nil
 }
 
-type PluginSet struct {
-	elements        map[Plugin]uint32
-	elementSequence []Plugin
+type Entries struct {
+	elements []Entry
 }
 
-func NewPluginSet(capacity int) *PluginSet {
-	set := new(PluginSet)
-	if capacity > 0 {
-		set.elements = make(map[Plugin]uint32, capacity)
-		set.elementSequence = make([]Plugin, 0, capacity)
-	} else {
-		set.elements = make(map[Plugin]uint32)
+func NewEntries(capacity int) *Entries {
+	return &Entries{
+		elements: make([]Entry, 0, capacity),
 	}
-	return set
 }
 
-func NewPluginSetFromSlice(items []Plugin) *PluginSet {
-	set := NewPluginSet(len(items))
-	for _, item := range items {
-		set.Append(item)
+func NewEntriesFromSlice(slice []Entry) *Entries {
+	return &Entries{
+		elements: slice,
 	}
-	return set
 }
 
-func (set *PluginSet) Len() int {
-	if set == nil {
+func (s *Entries) Len() int {
+	if s == nil {
 		return 0
 	}
-	return len(set.elements)
-}
-
-func (set *PluginSet) IsEmpty() bool {
-	return set.Len() == 0
+	return len(s.elements)
 }
 
-func (set *PluginSet) ToSlice() []Plugin {
-	if set == nil {
-		return nil
-	}
-	s := make([]Plugin, set.Len())
-	copy(s, set.elementSequence)
-	return s
+func (s *Entries) IsEmpty() bool {
+	return s.Len() == 0
 }
 
-// NOTICE: efficient but unsafe
-func (set *PluginSet) ToSliceRef() []Plugin {
-	return set.elementSequence
+func (s *Entries) Append(items ...Entry) {
+	s.elements = append(s.elements, items...)
 }
 
-func (set *PluginSet) Append(keys ...Plugin) {
-	for _, key := range keys {
-		if _, ok := set.elements[key]; !ok {
-			set.elements[key] = uint32(len(set.elementSequence))
-			set.elementSequence = append(set.elementSequence, key)
-		}
+func (s *Entries) Clone() *Entries {
+	cloned := &Entries{
+		elements: make([]Entry, s.Len()),
 	}
+	copy(cloned.elements, s.elements)
+	return cloned
 }
 
-func (set *PluginSet) Clear() {
-	set.elements = make(map[Plugin]uint32)
-	set.elementSequence = set.elementSequence[:0]
+func (s *Entries) ToSlice() []Entry {
+	slice := make([]Entry, s.Len())
+	copy(slice, s.elements)
+	return slice
 }
 
-func (set *PluginSet) Clone() *PluginSet {
-	cloned := NewPluginSet(set.Len())
-	for idx, item := range set.elementSequence {
-		cloned.elements[item] = uint32(idx)
-		cloned.elementSequence = append(cloned.elementSequence, item)
-	}
-	return cloned
+func (s *Entries) ToSliceRef() []Entry {
+	return s.elements
 }
 
-func (set *PluginSet) Difference(another *PluginSet) *PluginSet {
-	difference := NewPluginSet(0)
-	set.ForEach(func(item Plugin) {
-		if !another.Contains(item) {
-			difference.Append(item)
-		}
-	})
-	return difference
+func (s *Entries) Clear() {
+	s.elements = s.elements[:0]
 }
 
-func (set *PluginSet) Equal(another *PluginSet) bool {
-	if set.Len() != another.Len() {
-		return false
+func (s *Entries) Insert(idx int, items ...Entry) {
+	if idx < 0 {
+		idx += s.Len()
 	}
-	return set.ContainsAll(another.elementSequence...)
-}
-
-// TODO keep order
-func (set *PluginSet) Intersect(another *PluginSet) *PluginSet {
-	intersection := NewPluginSet(0)
-	if set.Len() < another.Len() {
-		for item := range set.elements {
-			if another.Contains(item) {
-				intersection.Append(item)
-			}
-		}
-	} else {
-		for item := range another.elements {
-			if set.Contains(item) {
-				intersection.Append(item)
-			}
-		}
+	if l := len(s.elements) + len(items); l > cap(s.elements) {
+		// reallocate
+		result := make([]Entry, l)
+		copy(result, s.elements[:idx])
+		copy(result[idx:], items)
+		copy(result[idx+len(items):], s.elements[idx:])
+		s.elements = result
+		return
 	}
-	return intersection
-}
 
-func (set *PluginSet) Union(another *PluginSet) *PluginSet {
-	union := set.Clone()
-	union.InPlaceUnion(another)
-	return union
+	l := s.Len()
+	s.elements = append(s.elements, items...)
+	copy(s.elements[idx+len(items):], s.elements[idx:l])
+	copy(s.elements[idx:], items)
 }
 
-func (set *PluginSet) InPlaceUnion(another *PluginSet) {
-	another.ForEach(func(item Plugin) {
-		set.Append(item)
-	})
+func (s *Entries) Remove(idx int) {
+	if idx < 0 {
+		idx += s.Len()
+	}
+	s.elements = append(s.elements[:idx], s.elements[idx+1:]...)
 }
 
-func (set *PluginSet) IsProperSubsetOf(another *PluginSet) bool {
-	return !set.Equal(another) && set.IsSubsetOf(another)
+func (s *Entries) RemoveRange(from, to int) {
+	if from < 0 {
+		from += s.Len()
+	}
+	if to < 0 {
+		to += s.Len()
+	}
+	s.elements = append(s.elements[:from], s.elements[to+1:]...)
 }
 
-func (set *PluginSet) IsProperSupersetOf(another *PluginSet) bool {
-	return !set.Equal(another) && set.IsSupersetOf(another)
+func (s *Entries) RemoveFrom(idx int) {
+	if idx < 0 {
+		idx += s.Len()
+	}
+	s.elements = s.elements[:idx]
 }
 
-func (set *PluginSet) IsSubsetOf(another *PluginSet) bool {
-	if set.Len() > another.Len() {
-		return false
+func (s *Entries) RemoveTo(idx int) {
+	if idx < 0 {
+		idx += s.Len()
 	}
-	for item := range set.elements {
-		if !another.Contains(item) {
-			return false
-		}
+	s.elements = s.elements[idx+1:]
+}
+
+func (s *Entries) Concat(another *Entries) *Entries {
+	result := s.Clone()
+	if another.IsEmpty() {
+		return result
 	}
-	return true
+	result.Append(another.elements...)
+	return result
 }
 
-func (set *PluginSet) IsSupersetOf(another *PluginSet) bool {
-	return another.IsSubsetOf(set)
+func (s *Entries) InPlaceConcat(another *Entries) {
+	if another.IsEmpty() {
+		return
+	}
+	s.Append(another.elements...)
 }
 
-func (set *PluginSet) ForEach(f func(Plugin)) {
-	if set.IsEmpty() {
+func (s *Entries) ForEach(f func(Entry)) {
+	if s.IsEmpty() {
 		return
 	}
-	for _, item := range set.elementSequence {
+	for _, item := range s.elements {
 		f(item)
 	}
 }
 
-func (set *PluginSet) ForEachWithIndex(f func(int, Plugin)) {
-	if set.IsEmpty() {
+func (s *Entries) ForEachWithIndex(f func(int, Entry)) {
+	if s.IsEmpty() {
 		return
 	}
-	for idx, item := range set.elementSequence {
+	for idx, item := range s.elements {
 		f(idx, item)
 	}
 }
 
-func (set *PluginSet) Filter(f func(Plugin) bool) *PluginSet {
-	result := NewPluginSet(0)
-	set.ForEach(func(item Plugin) {
+func (s *Entries) Filter(f func(Entry) bool) *Entries {
+	result := NewEntries(0)
+	for _, item := range s.elements {
 		if f(item) {
 			result.Append(item)
 		}
-	})
+	}
 	return result
 }
 
-func (set *PluginSet) Remove(key Plugin) {
-	if idx, ok := set.elements[key]; ok {
-		l := set.Len()
-		delete(set.elements, key)
-		for ; idx < uint32(l-1); idx++ {
-			item := set.elementSequence[idx+1]
-			set.elementSequence[idx] = item
-			set.elements[item] = idx
-		}
-		set.elementSequence = set.elementSequence[:l-1]
+func (s *Entries) Index(idx int) *Entry {
+	if idx < 0 {
+		idx += s.Len()
 	}
+	return &s.elements[idx]
 }
 
-func (set *PluginSet) Contains(key Plugin) bool {
-	_, ok := set.elements[key]
-	return ok
+func (s *Entries) IndexRange(from, to int) *Entries {
+	if from < 0 {
+		from += s.Len()
+	}
+	if to < 0 {
+		to += s.Len()
+	}
+	return NewEntriesFromSlice(s.elements[from:to])
 }
 
-func (set *PluginSet) ContainsAny(keys ...Plugin) bool {
-	for _, key := range keys {
-		if set.Contains(key) {
-			return true
-		}
+func (s *Entries) IndexFrom(idx int) *Entries {
+	if idx < 0 {
+		idx += s.Len()
 	}
-	return false
+	return NewEntriesFromSlice(s.elements[idx:])
 }
 
-func (set *PluginSet) ContainsAll(keys ...Plugin) bool {
-	for _, key := range keys {
-		if !set.Contains(key) {
-			return false
-		}
+func (s *Entries) IndexTo(idx int) *Entries {
+	if idx < 0 {
+		idx += s.Len()
 	}
-	return true
+	return NewEntriesFromSlice(s.elements[:idx])
 }
 
-func (set *PluginSet) DoUntil(f func(Plugin) bool) int {
-	for idx, item := range set.elementSequence {
-		if f(item) {
+func (s *Entries) FindBy(f func(Entry) bool) int {
+	if s.IsEmpty() {
+		return -1
+	}
+	for idx, n := range s.elements {
+		if f(n) {
 			return idx
 		}
 	}
 	return -1
 }
 
-func (set *PluginSet) DoWhile(f func(Plugin) bool) int {
-	for idx, item := range set.elementSequence {
-		if !f(item) {
+func (s *Entries) FindLastBy(f func(Entry) bool) int {
+	for idx := s.Len() - 1; idx >= 0; idx-- {
+		if f(s.elements[idx]) {
 			return idx
 		}
 	}
 	return -1
 }
 
-func (set *PluginSet) DoUntilError(f func(Plugin) error) error {
-	for _, item := range set.elementSequence {
-		if err := f(item); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (set *PluginSet) All(f func(Plugin) bool) bool {
-	for item := range set.elements {
-		if !f(item) {
-			return false
-		}
-	}
-	return true
-}
-
-func (set *PluginSet) Any(f func(Plugin) bool) bool {
-	for item := range set.elements {
-		if f(item) {
-			return true
-		}
-	}
-	return false
-}
-
-func (set *PluginSet) FindBy(f func(Plugin) bool) *Plugin {
-	for _, item := range set.elementSequence {
-		if f(item) {
-			return &item
-		}
-	}
-	return nil
-}
-
-func (set *PluginSet) FindLastBy(f func(Plugin) bool) *Plugin {
-	for i := set.Len() - 1; i >= 0; i-- {
-		if item := set.elementSequence[i]; f(item) {
-			return &item
-		}
-	}
-	return nil
-}
-
-func (set *PluginSet) CountBy(f func(Plugin) bool) int {
-	count := 0
-	set.ForEach(func(item Plugin) {
+func (s *Entries) CountBy(f func(Entry) bool) uint {
+	count := uint(0)
+	s.ForEach(func(item Entry) {
 		if f(item) {
 			count++
 		}
@@ -1207,9 +1163,9 @@ func (set *PluginSet) CountBy(f func(Plugin) bool) int {
 	return count
 }
 
-func (set *PluginSet) GroupByBool(f func(Plugin) bool) (trueGroup *PluginSet, falseGroup *PluginSet) {
-	trueGroup, falseGroup = NewPluginSet(0), NewPluginSet(0)
-	set.ForEach(func(item Plugin) {
+func (s *Entries) GroupByBool(f func(Entry) bool) (trueGroup, falseGroup *Entries) {
+	trueGroup, falseGroup = NewEntries(0), NewEntries(0)
+	s.ForEach(func(item Entry) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -1219,13 +1175,13 @@ func (set *PluginSet) GroupByBool(f func(Plugin) bool) (trueGroup *PluginSet, fa
 	return trueGroup, falseGroup
 }
 
-func (set *PluginSet) GroupByStr(f func(Plugin) string) map[string]*PluginSet {
-	groups := make(map[string]*PluginSet)
-	set.ForEach(func(item Plugin) {
+func (s Entries) GroupByStr(f func(Entry) string) map[string]*Entries {
+	groups := make(map[string]*Entries)
+	s.ForEach(func(item Entry) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewPluginSet(0)
+			group = NewEntries(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -1233,13 +1189,13 @@ func (set *PluginSet) GroupByStr(f func(Plugin) string) map[string]*PluginSet {
 	return groups
 }
 
-func (set *PluginSet) GroupByInt(f func(Plugin) int) map[int]*PluginSet {
-	groups := make(map[int]*PluginSet)
-	set.ForEach(func(item Plugin) {
+func (s Entries) GroupByInt(f func(Entry) int) map[int]*Entries {
+	groups := make(map[int]*Entries)
+	s.ForEach(func(item Entry) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewPluginSet(0)
+			group = NewEntries(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -1247,13 +1203,13 @@ func (set *PluginSet) GroupByInt(f func(Plugin) int) map[int]*PluginSet {
 	return groups
 }
 
-func (set *PluginSet) GroupBy(f func(Plugin) interface{}) map[interface{}]*PluginSet {
-	groups := make(map[interface{}]*PluginSet)
-	set.ForEach(func(item Plugin) {
+func (s *Entries) GroupBy(f func(Entry) interface{}) map[interface{}]*Entries {
+	groups := make(map[interface{}]*Entries)
+	s.ForEach(func(item Entry) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewPluginSet(0)
+			group = NewEntries(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -1261,10 +1217,10 @@ func (set *PluginSet) GroupBy(f func(Plugin) interface{}) map[interface{}]*Plugi
 	return groups
 }
 
-// f: func(Plugin) T
+// f: func(Entry) T
 // return: []T
-func (set *PluginSet) Map(f interface{}) interface{} {
-	expected := "f should be func(Plugin)T"
+func (s *Entries) Map(f interface{}) interface{} {
+	expected := "f should be func(Entry)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -1273,7 +1229,7 @@ func (set *PluginSet) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(Plugin)).Elem()
+	elemType := reflect.TypeOf(new(Entry)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -1281,19 +1237,19 @@ func (set *PluginSet) Map(f interface{}) interface{} {
 		panic(expected)
 	}
 	outType := ft.Out(0)
-	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item Plugin) {
+	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
+	s.ForEach(func(item Entry) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(Plugin) *T
-//    func(Plugin) (T, bool)
-//    func(Plugin) (T, error)
+// f: func(Entry) *T
+//    func(Entry) (T, bool)
+//    func(Entry) (T, error)
 // return: []T
-func (set *PluginSet) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(Plugin) *T / func(Plugin) (T, bool) / func(Plugin) (T, error)"
+func (s *Entries) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(Entry) *T / func(Entry) (T, bool) / func(Entry) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -1303,13 +1259,13 @@ func (set *PluginSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(Plugin)).Elem() {
+	if in != reflect.TypeOf(new(Entry)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(Plugin) *T
+		// func(Entry) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -1326,7 +1282,7 @@ func (set *PluginSet) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(Plugin) (T, bool)
+			// func(Entry) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -1334,7 +1290,7 @@ func (set *PluginSet) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(Plugin) (T, error)
+			// func(Entry) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -1344,125 +1300,176 @@ func (set *PluginSet) FilterMap(f interface{}) interface{} {
 		} else {
 			panic(expected)
 		}
-	} else {
-		panic(expected)
+	} else {
+		panic(expected)
+	}
+
+	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
+	s.ForEach(func(item Entry) {
+		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
+		if val := filter(ret); val != nil {
+			result = reflect.Append(result, *val)
+		}
+	})
+	return result.Interface()
+}
+
+func (s *Entries) DoUntil(f func(Entry) bool) int {
+	for idx, item := range s.elements {
+		if f(item) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (s *Entries) DoWhile(f func(Entry) bool) int {
+	for idx, item := range s.elements {
+		if !f(item) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (s *Entries) DoUntilError(f func(Entry) error) error {
+	for _, item := range s.elements {
+		if err := f(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Entries) All(f func(Entry) bool) bool {
+	for _, item := range s.elements {
+		if !f(item) {
+			return false
+		}
 	}
+	return true
+}
 
-	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item Plugin) {
-		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
-		if val := filter(ret); val != nil {
-			result = reflect.Append(result, *val)
+func (s *Entries) Any(f func(Entry) bool) bool {
+	for _, item := range s.elements {
+		if f(item) {
+			return true
 		}
-	})
-	return result.Interface()
+	}
+	return false
 }
 
-func (set *PluginSet) Reduce(f func(Plugin, Plugin) Plugin) Plugin {
-	if set.IsEmpty() {
-		var defaultVal Plugin
+func (s *Entries) Reduce(f func(Entry, Entry) Entry) Entry {
+	if s.IsEmpty() {
+		var defaultVal Entry
 		return defaultVal
 	}
-	ret := set.elementSequence[0]
-	for _, item := range set.elementSequence[1:] {
+	ret := s.elements[0]
+	for _, item := range s.elements[1:] {
 		ret = f(ret, item)
 	}
 	return ret
 }
 
-func (set *PluginSet) Fold(init Plugin, f func(Plugin, Plugin) Plugin) Plugin {
-	if set.IsEmpty() {
+func (s *Entries) Fold(init Entry, f func(Entry, Entry) Entry) Entry {
+	if s.IsEmpty() {
 		return init
 	}
-	for _, item := range set.elementSequence {
+	for _, item := range s.elements {
 		init = f(init, item)
 	}
 	return init
 }
 
-func (set *PluginSet) String() string {
-	return fmt.Sprint(set.elementSequence)
+func (s *Entries) String() string {
+	return fmt.Sprint(s.elements)
 }
 
-func (set PluginSet) MarshalJSON() ([]byte, error) {
-	return json.Marshal(set.ToSlice())
+func (s Entries) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.elements)
 }
 
-func (set *PluginSet) UnmarshalJSON(b []byte) error {
-	s := make([]Plugin, 0)
-	err := json.Unmarshal(b, &s)
-	if err != nil {
-		return err
-	}
-	*set = *NewPluginSetFromSlice(s)
-	return nil
+func (s *Entries) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &s.elements)
 }
 
-type ValueSet struct {
-	elements        map[Value]uint32
-	elementSequence []Value
+type ImportSet struct {
+	cmp             func(i, j Import) bool
+	elements        map[Import]uint32
+	elementSequence []Import
 }
 
-func NewValueSet(capacity int) *ValueSet {
-	set := new(ValueSet)
+func NewImportSet(capacity int, cmp func(i, j Import) bool) *ImportSet {
+	set := new(ImportSet)
 	if capacity > 0 {
-		set.elements = make(map[Value]uint32, capacity)
-		set.elementSequence = make([]Value, 0, capacity)
+		set.elements = make(map[Import]uint32, capacity)
+		set.elementSequence = make([]Import, 0, capacity)
 	} else {
-		set.elements = make(map[Value]uint32)
+		set.elements = make(map[Import]uint32)
 	}
+	set.cmp = cmp
 	return set
 }
 
-func NewValueSetFromSlice(items []Value) *ValueSet {
-	set := NewValueSet(len(items))
+func NewImportSetFromSlice(items []Import, cmp func(i, j Import) bool) *ImportSet {
+	set := NewImportSet(len(items), cmp)
 	for _, item := range items {
 		set.Append(item)
 	}
 	return set
 }
 
-func (set *ValueSet) Len() int {
+func (set *ImportSet) Len() int {
 	if set == nil {
 		return 0
 	}
 	return len(set.elements)
 }
 
-func (set *ValueSet) IsEmpty() bool {
+func (set *ImportSet) IsEmpty() bool {
 	return set.Len() == 0
 }
 
-func (set *ValueSet) ToSlice() []Value {
+func (set *ImportSet) ToSlice() []Import {
 	if set == nil {
 		return nil
 	}
-	s := make([]Value, set.Len())
+	s := make([]Import, set.Len())
 	copy(s, set.elementSequence)
 	return s
 }
 
 // NOTICE: efficient but unsafe
-func (set *ValueSet) ToSliceRef() []Value {
+func (set *ImportSet) ToSliceRef() []Import {
 	return set.elementSequence
 }
 
-func (set *ValueSet) Append(keys ...Value) {
+func (set *ImportSet) Append(keys ...Import) {
 	for _, key := range keys {
 		if _, ok := set.elements[key]; !ok {
-			set.elements[key] = uint32(len(set.elementSequence))
+			idx := sort.Search(len(set.elementSequence), func(i int) bool {
+				return set.cmp(key, set.elementSequence[i])
+			})
+			l := len(set.elementSequence)
 			set.elementSequence = append(set.elementSequence, key)
+			for i := l; i > idx; i-- {
+				set.elements[set.elementSequence[i]] = uint32(i + 1)
+				set.elementSequence[i] = set.elementSequence[i-1]
+			}
+			set.elements[set.elementSequence[idx]] = uint32(idx + 1)
+			set.elementSequence[idx] = key
+			set.elements[key] = uint32(idx)
 		}
 	}
 }
 
-func (set *ValueSet) Clear() {
-	set.elements = make(map[Value]uint32)
+func (set *ImportSet) Clear() {
+	set.elements = make(map[Import]uint32)
 	set.elementSequence = set.elementSequence[:0]
 }
 
-func (set *ValueSet) Clone() *ValueSet {
-	cloned := NewValueSet(set.Len())
+func (set *ImportSet) Clone() *ImportSet {
+	cloned := NewImportSet(set.Len(), set.cmp)
 	for idx, item := range set.elementSequence {
 		cloned.elements[item] = uint32(idx)
 		cloned.elementSequence = append(cloned.elementSequence, item)
@@ -1470,9 +1477,9 @@ func (set *ValueSet) Clone() *ValueSet {
 	return cloned
 }
 
-func (set *ValueSet) Difference(another *ValueSet) *ValueSet {
-	difference := NewValueSet(0)
-	set.ForEach(func(item Value) {
+func (set *ImportSet) Difference(another *ImportSet) *ImportSet {
+	difference := NewImportSet(0, set.cmp)
+	set.ForEach(func(item Import) {
 		if !another.Contains(item) {
 			difference.Append(item)
 		}
@@ -1480,16 +1487,15 @@ func (set *ValueSet) Difference(another *ValueSet) *ValueSet {
 	return difference
 }
 
-func (set *ValueSet) Equal(another *ValueSet) bool {
+func (set *ImportSet) Equal(another *ImportSet) bool {
 	if set.Len() != another.Len() {
 		return false
 	}
 	return set.ContainsAll(another.elementSequence...)
 }
 
-// TODO keep order
-func (set *ValueSet) Intersect(another *ValueSet) *ValueSet {
-	intersection := NewValueSet(0)
+func (set *ImportSet) Intersect(another *ImportSet) *ImportSet {
+	intersection := NewImportSet(0, set.cmp)
 	if set.Len() < another.Len() {
 		for item := range set.elements {
 			if another.Contains(item) {
@@ -1506,27 +1512,27 @@ func (set *ValueSet) Intersect(another *ValueSet) *ValueSet {
 	return intersection
 }
 
-func (set *ValueSet) Union(another *ValueSet) *ValueSet {
+func (set *ImportSet) Union(another *ImportSet) *ImportSet {
 	union := set.Clone()
 	union.InPlaceUnion(another)
 	return union
 }
 
-func (set *ValueSet) InPlaceUnion(another *ValueSet) {
-	another.ForEach(func(item Value) {
+func (set *ImportSet) InPlaceUnion(another *ImportSet) {
+	another.ForEach(func(item Import) {
 		set.Append(item)
 	})
 }
 
-func (set *ValueSet) IsProperSubsetOf(another *ValueSet) bool {
+func (set *ImportSet) IsProperSubsetOf(another *ImportSet) bool {
 	return !set.Equal(another) && set.IsSubsetOf(another)
 }
 
-func (set *ValueSet) IsProperSupersetOf(another *ValueSet) bool {
+func (set *ImportSet) IsProperSupersetOf(another *ImportSet) bool {
 	return !set.Equal(another) && set.IsSupersetOf(another)
 }
 
-func (set *ValueSet) IsSubsetOf(another *ValueSet) bool {
+func (set *ImportSet) IsSubsetOf(another *ImportSet) bool {
 	if set.Len() > another.Len() {
 		return false
 	}
@@ -1538,11 +1544,11 @@ func (set *ValueSet) IsSubsetOf(another *ValueSet) bool {
 	return true
 }
 
-func (set *ValueSet) IsSupersetOf(another *ValueSet) bool {
+func (set *ImportSet) IsSupersetOf(another *ImportSet) bool {
 	return another.IsSubsetOf(set)
 }
 
-func (set *ValueSet) ForEach(f func(Value)) {
+func (set *ImportSet) ForEach(f func(Import)) {
 	if set.IsEmpty() {
 		return
 	}
@@ -1551,7 +1557,7 @@ func (set *ValueSet) ForEach(f func(Value)) {
 	}
 }
 
-func (set *ValueSet) ForEachWithIndex(f func(int, Value)) {
+func (set *ImportSet) ForEachWithIndex(f func(int, Import)) {
 	if set.IsEmpty() {
 		return
 	}
@@ -1560,9 +1566,9 @@ func (set *ValueSet) ForEachWithIndex(f func(int, Value)) {
 	}
 }
 
-func (set *ValueSet) Filter(f func(Value) bool) *ValueSet {
-	result := NewValueSet(0)
-	set.ForEach(func(item Value) {
+func (set *ImportSet) Filter(f func(Import) bool) *ImportSet {
+	result := NewImportSet(0, set.cmp)
+	set.ForEach(func(item Import) {
 		if f(item) {
 			result.Append(item)
 		}
@@ -1570,7 +1576,7 @@ func (set *ValueSet) Filter(f func(Value) bool) *ValueSet {
 	return result
 }
 
-func (set *ValueSet) Remove(key Value) {
+func (set *ImportSet) Remove(key Import) {
 	if idx, ok := set.elements[key]; ok {
 		l := set.Len()
 		delete(set.elements, key)
@@ -1583,12 +1589,12 @@ func (set *ValueSet) Remove(key Value) {
 	}
 }
 
-func (set *ValueSet) Contains(key Value) bool {
+func (set *ImportSet) Contains(key Import) bool {
 	_, ok := set.elements[key]
 	return ok
 }
 
-func (set *ValueSet) ContainsAny(keys ...Value) bool {
+func (set *ImportSet) ContainsAny(keys ...Import) bool {
 	for _, key := range keys {
 		if set.Contains(key) {
 			return true
@@ -1597,7 +1603,7 @@ func (set *ValueSet) ContainsAny(keys ...Value) bool {
 	return false
 }
 
-func (set *ValueSet) ContainsAll(keys ...Value) bool {
+func (set *ImportSet) ContainsAll(keys ...Import) bool {
 	for _, key := range keys {
 		if !set.Contains(key) {
 			return false
@@ -1606,7 +1612,7 @@ func (set *ValueSet) ContainsAll(keys ...Value) bool {
 	return true
 }
 
-func (set *ValueSet) DoUntil(f func(Value) bool) int {
+func (set *ImportSet) DoUntil(f func(Import) bool) int {
 	for idx, item := range set.elementSequence {
 		if f(item) {
 			return idx
@@ -1615,7 +1621,7 @@ func (set *ValueSet) DoUntil(f func(Value) bool) int {
 	return -1
 }
 
-func (set *ValueSet) DoWhile(f func(Value) bool) int {
+func (set *ImportSet) DoWhile(f func(Import) bool) int {
 	for idx, item := range set.elementSequence {
 		if !f(item) {
 			return idx
@@ -1624,7 +1630,7 @@ func (set *ValueSet) DoWhile(f func(Value) bool) int {
 	return -1
 }
 
-func (set *ValueSet) DoUntilError(f func(Value) error) error {
+func (set *ImportSet) DoUntilError(f func(Import) error) error {
 	for _, item := range set.elementSequence {
 		if err := f(item); err != nil {
 			return err
@@ -1633,7 +1639,7 @@ func (set *ValueSet) DoUntilError(f func(Value) error) error {
 	return nil
 }
 
-func (set *ValueSet) All(f func(Value) bool) bool {
+func (set *ImportSet) All(f func(Import) bool) bool {
 	for item := range set.elements {
 		if !f(item) {
 			return false
@@ -1642,7 +1648,7 @@ func (set *ValueSet) All(f func(Value) bool) bool {
 	return true
 }
 
-func (set *ValueSet) Any(f func(Value) bool) bool {
+func (set *ImportSet) Any(f func(Import) bool) bool {
 	for item := range set.elements {
 		if f(item) {
 			return true
@@ -1651,7 +1657,7 @@ func (set *ValueSet) Any(f func(Value) bool) bool {
 	return false
 }
 
-func (set *ValueSet) FindBy(f func(Value) bool) *Value {
+func (set *ImportSet) FindBy(f func(Import) bool) *Import {
 	for _, item := range set.elementSequence {
 		if f(item) {
 			return &item
@@ -1660,7 +1666,7 @@ func (set *ValueSet) FindBy(f func(Value) bool) *Value {
 	return nil
 }
 
-func (set *ValueSet) FindLastBy(f func(Value) bool) *Value {
+func (set *ImportSet) FindLastBy(f func(Import) bool) *Import {
 	for i := set.Len() - 1; i >= 0; i-- {
 		if item := set.elementSequence[i]; f(item) {
 			return &item
@@ -1669,9 +1675,9 @@ func (set *ValueSet) FindLastBy(f func(Value) bool) *Value {
 	return nil
 }
 
-func (set *ValueSet) CountBy(f func(Value) bool) int {
+func (set *ImportSet) CountBy(f func(Import) bool) int {
 	count := 0
-	set.ForEach(func(item Value) {
+	set.ForEach(func(item Import) {
 		if f(item) {
 			count++
 		}
@@ -1679,9 +1685,9 @@ func (set *ValueSet) CountBy(f func(Value) bool) int {
 	return count
 }
 
-func (set *ValueSet) GroupByBool(f func(Value) bool) (trueGroup *ValueSet, falseGroup *ValueSet) {
-	trueGroup, falseGroup = NewValueSet(0), NewValueSet(0)
-	set.ForEach(func(item Value) {
+func (set *ImportSet) GroupByBool(f func(Import) bool) (trueGroup *ImportSet, falseGroup *ImportSet) {
+	trueGroup, falseGroup = NewImportSet(0, set.cmp), NewImportSet(0, set.cmp)
+	set.ForEach(func(item Import) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -1691,13 +1697,13 @@ func (set *ValueSet) GroupByBool(f func(Value) bool) (trueGroup *ValueSet, false
 	return trueGroup, falseGroup
 }
 
-func (set *ValueSet) GroupByStr(f func(Value) string) map[string]*ValueSet {
-	groups := make(map[string]*ValueSet)
-	set.ForEach(func(item Value) {
+func (set *ImportSet) GroupByStr(f func(Import) string) map[string]*ImportSet {
+	groups := make(map[string]*ImportSet)
+	set.ForEach(func(item Import) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewValueSet(0)
+			group = NewImportSet(0, set.cmp)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -1705,13 +1711,13 @@ func (set *ValueSet) GroupByStr(f func(Value) string) map[string]*ValueSet {
 	return groups
 }
 
-func (set *ValueSet) GroupByInt(f func(Value) int) map[int]*ValueSet {
-	groups := make(map[int]*ValueSet)
-	set.ForEach(func(item Value) {
+func (set *ImportSet) GroupByInt(f func(Import) int) map[int]*ImportSet {
+	groups := make(map[int]*ImportSet)
+	set.ForEach(func(item Import) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewValueSet(0)
+			group = NewImportSet(0, set.cmp)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -1719,13 +1725,13 @@ func (set *ValueSet) GroupByInt(f func(Value) int) map[int]*ValueSet {
 	return groups
 }
 
-func (set *ValueSet) GroupBy(f func(Value) interface{}) map[interface{}]*ValueSet {
-	groups := make(map[interface{}]*ValueSet)
-	set.ForEach(func(item Value) {
+func (set *ImportSet) GroupBy(f func(Import) interface{}) map[interface{}]*ImportSet {
+	groups := make(map[interface{}]*ImportSet)
+	set.ForEach(func(item Import) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewValueSet(0)
+			group = NewImportSet(0, set.cmp)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -1733,10 +1739,10 @@ func (set *ValueSet) GroupBy(f func(Value) interface{}) map[interface{}]*ValueSe
 	return groups
 }
 
-// f: func(Value) T
+// f: func(Import) T
 // return: []T
-func (set *ValueSet) Map(f interface{}) interface{} {
-	expected := "f should be func(Value)T"
+func (set *ImportSet) Map(f interface{}) interface{} {
+	expected := "f should be func(Import)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -1745,7 +1751,7 @@ func (set *ValueSet) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(Value)).Elem()
+	elemType := reflect.TypeOf(new(Import)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -1754,18 +1760,18 @@ func (set *ValueSet) Map(f interface{}) interface{} {
 	}
 	outType := ft.Out(0)
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item Value) {
+	set.ForEach(func(item Import) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(Value) *T
-//    func(Value) (T, bool)
-//    func(Value) (T, error)
+// f: func(Import) *T
+//    func(Import) (T, bool)
+//    func(Import) (T, error)
 // return: []T
-func (set *ValueSet) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(Value) *T / func(Value) (T, bool) / func(Value) (T, error)"
+func (set *ImportSet) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(Import) *T / func(Import) (T, bool) / func(Import) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -1775,13 +1781,13 @@ func (set *ValueSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(Value)).Elem() {
+	if in != reflect.TypeOf(new(Import)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(Value) *T
+		// func(Import) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -1798,7 +1804,7 @@ func (set *ValueSet) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(Value) (T, bool)
+			// func(Import) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -1806,7 +1812,7 @@ func (set *ValueSet) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(Value) (T, error)
+			// func(Import) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -1821,7 +1827,7 @@ func (set *ValueSet) FilterMap(f interface{}) interface{} {
 	}
 
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item Value) {
+	set.ForEach(func(item Import) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -1830,9 +1836,9 @@ func (set *ValueSet) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (set *ValueSet) Reduce(f func(Value, Value) Value) Value {
+func (set *ImportSet) Reduce(f func(Import, Import) Import) Import {
 	if set.IsEmpty() {
-		var defaultVal Value
+		var defaultVal Import
 		return defaultVal
 	}
 	ret := set.elementSequence[0]
@@ -1842,7 +1848,7 @@ func (set *ValueSet) Reduce(f func(Value, Value) Value) Value {
 	return ret
 }
 
-func (set *ValueSet) Fold(init Value, f func(Value, Value) Value) Value {
+func (set *ImportSet) Fold(init Import, f func(Import, Import) Import) Import {
 	if set.IsEmpty() {
 		return init
 	}
@@ -1852,20 +1858,14 @@ func (set *ValueSet) Fold(init Value, f func(Value, Value) Value) Value {
 	return init
 }
 
-func (set *ValueSet) String() string {
+func (set *ImportSet) String() string {
 	return fmt.Sprint(set.elementSequence)
 }
 
-func (set ValueSet) MarshalJSON() ([]byte, error) {
+func (set ImportSet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(set.ToSlice())
 }
 
-func (set *ValueSet) UnmarshalJSON(b []byte) error {
-	s := make([]Value, 0)
-	err := json.Unmarshal(b, &s)
-	if err != nil {
-		return err
-	}
-	*set = *NewValueSetFromSlice(s)
-	return nil
+func (set *ImportSet) UnmarshalJSON(b []byte) error {
+	return fmt.Errorf("unsupported")
 }
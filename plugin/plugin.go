@@ -5,8 +5,11 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"io"
+	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/nextzhou/goderive/utils"
 )
@@ -38,10 +41,13 @@ func (ot OptionType) IsIdent() bool {
 // TODO pop operation
 // TODO arg with default value as flag
 type Options struct {
-	// TODO keep order
 	Flags          map[Flag]utils.TriBool
 	Args           map[string]Arg
 	ExistingOption map[string]OptionType
+	// keys of flags and args in the order they were set
+	Order []string
+	// source position of flags and arg keys, if known
+	Positions map[string]token.Position
 }
 
 func NewOptions() *Options {
@@ -49,19 +55,63 @@ func NewOptions() *Options {
 		Flags:          make(map[Flag]utils.TriBool),
 		Args:           make(map[string]Arg),
 		ExistingOption: make(map[string]OptionType),
+		Positions:      make(map[string]token.Position),
 	}
 }
 
+func (opts *Options) setPosition(opt string, pos token.Position) {
+	if pos.IsValid() {
+		opts.Positions[opt] = pos
+	}
+}
+
+// errorAt attaches position of the option to err
+func (opts *Options) errorAt(opt string, err error) error {
+	return errorAtPosition(opts.Positions[opt], err)
+}
+
+func errorAtPosition(pos token.Position, err error) error {
+	if !pos.IsValid() {
+		return err
+	}
+	return &utils.PositionedError{Pos: pos, Err: err}
+}
+
+// position of the n-th byte after pos, in the same line
+func shiftPosition(pos token.Position, n int) token.Position {
+	if pos.IsValid() {
+		pos.Offset += n
+		pos.Column += n
+	}
+	return pos
+}
+
 func (opts *Options) SetFlag(flag string, val utils.TriBool) {
-	opts.Flags[Flag(flag)] = val
+	opts.setFlag(flag, val)
 	opts.ExistingOption[flag] = OptionTypeFlag
 }
 
 func (opts *Options) SetArg(arg Arg) {
-	opts.Args[arg.Key] = arg
+	opts.setArg(arg)
 	opts.ExistingOption[arg.Key] = OptionTypeArgKey
 }
 
+// setFlag sets flag without marking it as existing option, e.g. default value
+func (opts *Options) setFlag(flag string, val utils.TriBool) {
+	if _, ok := opts.Flags[Flag(flag)]; !ok {
+		opts.Order = append(opts.Order, flag)
+	}
+	opts.Flags[Flag(flag)] = val
+}
+
+// setArg sets arg without marking it as existing option, e.g. default value
+func (opts *Options) setArg(arg Arg) {
+	if _, ok := opts.Args[arg.Key]; !ok {
+		opts.Order = append(opts.Order, arg.Key)
+	}
+	opts.Args[arg.Key] = arg
+}
+
 func (opts Options) ValidateOption(optType OptionType, opt string) error {
 	if optType.IsIdent() && !utils.ValidateIdentName(opt) {
 		return &utils.InvalidIdentError{Type: string(optType), Ident: opt}
@@ -73,15 +123,23 @@ func (opts Options) ValidateOption(optType OptionType, opt string) error {
 }
 
 func ParseOptions(optsStr string) (*Options, error) {
+	return ParseOptionsAt(optsStr, token.Position{})
+}
+
+// ParseOptionsAt parses options string located at pos, and records position of each option.
+func ParseOptionsAt(optsStr string, pos token.Position) (*Options, error) {
 	ret := NewOptions()
 
-	optsStr = strings.TrimSpace(optsStr)
-	if optsStr == "" {
+	if strings.TrimSpace(optsStr) == "" {
 		return ret, nil
 	}
 
+	// offset of current option in optsStr
+	offset := 0
 	opts := strings.Split(optsStr, OptionSep)
 	for _, opt := range opts {
+		optPos := shiftPosition(pos, offset+len(opt)-len(strings.TrimLeftFunc(opt, unicode.IsSpace)))
+		offset += len(opt) + len(OptionSep)
 		opt = strings.TrimSpace(opt)
 		sepIdx := strings.Index(opt, ArgSep)
 		// flag
@@ -92,9 +150,10 @@ func ParseOptions(optsStr string) (*Options, error) {
 				opt = strings.TrimSpace(opt[1:])
 			}
 			if err := ret.ValidateOption(OptionTypeFlag, opt); err != nil {
-				return nil, err
+				return nil, errorAtPosition(optPos, err)
 			}
 			ret.SetFlag(opt, utils.BoolToTri(flagVal))
+			ret.setPosition(opt, optPos)
 			continue
 		}
 		// arg
@@ -103,7 +162,7 @@ func ParseOptions(optsStr string) (*Options, error) {
 		key = strings.TrimSpace(key)
 
 		if err := ret.ValidateOption(OptionTypeArgKey, key); err != nil {
-			return nil, err
+			return nil, errorAtPosition(optPos, err)
 		}
 
 		arg.Key = key
@@ -111,11 +170,12 @@ func ParseOptions(optsStr string) (*Options, error) {
 		for _, val := range vals {
 			val = strings.TrimSpace(val)
 			if err := ret.ValidateOption(OptionTypeArgValue, val); err != nil {
-				return nil, err
+				return nil, errorAtPosition(optPos, err)
 			}
 			arg.Values = append(arg.Values, Value(val))
 		}
 		ret.SetArg(arg)
+		ret.setPosition(key, optPos)
 	}
 	return ret, nil
 }
@@ -124,26 +184,97 @@ func (opts *Options) Merge(another *Options) error {
 	if another == nil {
 		return nil
 	}
-	for flag, val := range another.Flags {
-		if err := opts.ValidateOption(OptionTypeFlag, string(flag)); err != nil {
-			return err
+	for _, key := range another.Order {
+		if val, ok := another.Flags[Flag(key)]; ok {
+			if err := opts.ValidateOption(OptionTypeFlag, key); err != nil {
+				return another.errorAt(key, err)
+			}
+			opts.SetFlag(key, val)
+		} else {
+			if err := opts.ValidateOption(OptionTypeArgKey, key); err != nil {
+				return another.errorAt(key, err)
+			}
+			opts.SetArg(another.Args[key])
 		}
-		opts.SetFlag(string(flag), val)
+		opts.setPosition(key, another.Positions[key])
 	}
+	return nil
+}
 
-	for key, arg := range another.Args {
-		if err := opts.ValidateOption(OptionTypeArgKey, key); err != nil {
-			return err
+// MergeDefault sets options of defaults which are not set yet, e.g. default options from configuration.
+func (opts *Options) MergeDefault(defaults *Options) {
+	if defaults == nil {
+		return
+	}
+	for _, key := range defaults.Order {
+		if opts.ExistingOption[key] != OptionTypeNone {
+			continue
+		}
+		if val, ok := defaults.Flags[Flag(key)]; ok {
+			opts.SetFlag(key, val)
+		} else {
+			opts.SetArg(defaults.Args[key])
 		}
-		opts.SetArg(arg)
+		opts.setPosition(key, defaults.Positions[key])
+	}
+}
+
+// Override sets options of another even if they are already set, e.g. options forced from command line.
+// Positions of the overridden options are dropped unless another has.
+func (opts *Options) Override(another *Options) {
+	if another == nil {
+		return
+	}
+	for _, key := range another.Order {
+		_, isFlag := opts.Flags[Flag(key)]
+		_, isArg := opts.Args[key]
+		if !isFlag && !isArg {
+			opts.Order = append(opts.Order, key)
+		}
+		delete(opts.Flags, Flag(key))
+		delete(opts.Args, key)
+		delete(opts.Positions, key)
+		if val, ok := another.Flags[Flag(key)]; ok {
+			opts.Flags[Flag(key)] = val
+			opts.ExistingOption[key] = OptionTypeFlag
+		} else {
+			opts.Args[key] = another.Args[key]
+			opts.ExistingOption[key] = OptionTypeArgKey
+		}
+		opts.setPosition(key, another.Positions[key])
 	}
-	return nil
 }
 
 func (opts *Options) IsEmpty() bool {
 	return opts == nil || len(opts.Flags)+len(opts.Args) == 0
 }
 
+// String returns options in the format of derive comment, ordered as they were set.
+// Flags of undefined value are omitted.
+func (opts *Options) String() string {
+	if opts == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(opts.Order))
+	for _, key := range opts.Order {
+		if val, ok := opts.Flags[Flag(key)]; ok {
+			if val.IsTrue() {
+				parts = append(parts, key)
+			} else if val.IsFalse() {
+				parts = append(parts, "!"+key)
+			}
+			continue
+		}
+		arg := opts.Args[key]
+		values := make([]string, len(arg.Values))
+		for i, value := range arg.Values {
+			values[i] = value.Str()
+		}
+		parts = append(parts, key+ArgSep+strings.Join(values, ArgValueSep))
+	}
+	return strings.Join(parts, OptionSep)
+}
+
 type Flag string
 
 type Arg struct {
@@ -341,16 +472,13 @@ func (desc Description) validateFlags(opts *Options) error {
 		delete(uncheckedFlags, flag.Key)
 		// set default value
 		if _, ok := opts.Flags[Flag(flag.Key)]; !ok {
-			opts.Flags[Flag(flag.Key)] = flag.Default
+			opts.setFlag(flag.Key, flag.Default)
 		}
 	}
 
 	if len(uncheckedFlags) > 0 && !desc.AllowUnexpectedlyFlag {
-		flags := make([]string, 0, len(uncheckedFlags))
-		for flag := range uncheckedFlags {
-			flags = append(flags, flag)
-		}
-		return &utils.UnexpectedError{Type: string(OptionTypeFlag), Idents: flags}
+		flags := opts.filterOrder(uncheckedFlags)
+		return opts.errorAt(flags[0], &utils.UnexpectedError{Type: string(OptionTypeFlag), Idents: flags})
 	}
 	return nil
 }
@@ -364,51 +492,113 @@ func (desc Description) validateArgs(opts *Options) error {
 	for _, validArg := range desc.ValidArgs {
 		delete(uncheckedArgs, validArg.Key)
 		if arg, ok := opts.Args[validArg.Key]; ok {
-			if validArg.IsMultipleValues {
-				if !validArg.AllowEmpty && len(arg.Values) == 0 {
-					return &utils.ArgEmptyValueError{ArgKey: validArg.Key}
-				}
-			} else {
-				if len(arg.Values) != 1 {
-					return &utils.ArgNotSingleValueError{ArgKey: validArg.Key}
-				}
-			}
-			if !validArg.ValidValues.IsEmpty() {
-				for _, value := range arg.Values {
-					if !validArg.ValidValues.Contains(value) {
-						return &utils.UnsupportedError{Type: OptionTypeArgValue, Idents: []string{value.Str()}}
-					}
-				}
-
+			if err := validArg.validate(opts, arg); err != nil {
+				return err
 			}
 		} else if validArg.DefaultValue != nil {
 			// set default value
-			opts.Args[validArg.Key] = Arg{Key: validArg.Key, Values: []Value{*validArg.DefaultValue}}
+			opts.setArg(Arg{Key: validArg.Key, Values: []Value{*validArg.DefaultValue}})
 		} else if !validArg.AllowEmpty {
 			return &utils.ArgEmptyValueError{ArgKey: validArg.Key}
 		}
 	}
 
 	if len(uncheckedArgs) > 0 && !desc.AllowUnexpectedlyArg {
-		args := make([]string, 0, len(uncheckedArgs))
-		for arg := range uncheckedArgs {
-			args = append(args, arg)
+		args := opts.filterOrder(uncheckedArgs)
+		return opts.errorAt(args[0], &utils.UnexpectedError{Type: string(OptionTypeArgKey), Idents: args})
+	}
+	return nil
+}
+
+func (validArg ArgDescription) validate(opts *Options, arg Arg) error {
+	if validArg.IsMultipleValues {
+		if !validArg.AllowEmpty && len(arg.Values) == 0 {
+			return opts.errorAt(validArg.Key, &utils.ArgEmptyValueError{ArgKey: validArg.Key})
+		}
+	} else {
+		if len(arg.Values) != 1 {
+			return opts.errorAt(validArg.Key, &utils.ArgNotSingleValueError{ArgKey: validArg.Key})
+		}
+	}
+	if !validArg.ValidValues.IsEmpty() {
+		for _, value := range arg.Values {
+			if !validArg.ValidValues.Contains(value) {
+				return opts.errorAt(validArg.Key, &utils.UnsupportedError{Type: OptionTypeArgValue, Idents: []string{value.Str()}})
+			}
 		}
-		return &utils.UnexpectedError{Type: string(OptionTypeArgKey), Idents: args}
 	}
 	return nil
 }
 
+// ValidatePartial validates the flags and args set in opts, which may be a part of the options of a type.
+// Unlike Validate, missing args are not required, and no default value is set.
+func (desc Description) ValidatePartial(opts *Options) error {
+	unexpectedFlags := make(map[string]bool)
+	for flag := range opts.Flags {
+		unexpectedFlags[string(flag)] = true
+	}
+	for _, flag := range desc.ValidFlags {
+		delete(unexpectedFlags, flag.Key)
+	}
+	if len(unexpectedFlags) > 0 && !desc.AllowUnexpectedlyFlag {
+		flags := opts.filterOrder(unexpectedFlags)
+		return opts.errorAt(flags[0], &utils.UnexpectedError{Type: string(OptionTypeFlag), Idents: flags})
+	}
+
+	unexpectedArgs := make(map[string]bool)
+	for key := range opts.Args {
+		unexpectedArgs[key] = true
+	}
+	for _, validArg := range desc.ValidArgs {
+		delete(unexpectedArgs, validArg.Key)
+		if arg, ok := opts.Args[validArg.Key]; ok {
+			if err := validArg.validate(opts, arg); err != nil {
+				return err
+			}
+		}
+	}
+	if len(unexpectedArgs) > 0 && !desc.AllowUnexpectedlyArg {
+		args := opts.filterOrder(unexpectedArgs)
+		return opts.errorAt(args[0], &utils.UnexpectedError{Type: string(OptionTypeArgKey), Idents: args})
+	}
+	return nil
+}
+
+// filterOrder returns keys in the given set, ordered as they were set.
+// Keys not set by Options methods are sorted and placed at the end.
+func (opts *Options) filterOrder(keys map[string]bool) []string {
+	ordered := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range opts.Order {
+		if keys[key] && !seen[key] {
+			ordered = append(ordered, key)
+			seen[key] = true
+		}
+	}
+	var rest []string
+	for key := range keys {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(ordered, rest...)
+}
+
 type TypeInfo struct {
 	Name     string
 	Assigned string
 	Ast      ast.Expr
+	// file set of Ast, for error positions
+	Fset *token.FileSet
 }
 
 // derive-slice: Rename=Entries
 type Entry struct {
 	Plugin string
 	Opts   *Options
+	// position of the first derive comment of the plugin
+	Pos token.Position
 }
 
 func (e *Entry) IsEmpty() bool {
@@ -462,6 +652,7 @@ func MakeImportFromAst(i *ast.ImportSpec) Import {
 	return ret
 }
 
+// Env is shared by all types of a source file, plugins may generate code concurrently and must not modify it.
 type Env struct {
 	PkgName string
 	Imports *ImportSet
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"go/token"
 	"testing"
 
 	"github.com/nextzhou/goderive/utils"
@@ -76,6 +77,118 @@ func TestParseOptions(t *testing.T) {
 
 }
 
+func TestOptionPositions(t *testing.T) {
+	Convey("option positions", t, func() {
+		pos := token.Position{Filename: "a.go", Offset: 100, Line: 3, Column: 15}
+		opts, err := ParseOptionsAt(" flag1;  !flag2 ; key=val", pos)
+		So(err, ShouldBeNil)
+		So(opts.Positions["flag1"].String(), ShouldEqual, "a.go:3:16")
+		So(opts.Positions["flag2"].String(), ShouldEqual, "a.go:3:24")
+		So(opts.Positions["key"].String(), ShouldEqual, "a.go:3:33")
+
+		_, err = ParseOptionsAt("flag;flag", pos)
+		So(err, ShouldBeError, `a.go:3:20: already existed flag "flag"`)
+
+		desc := Description{ValidFlags: []FlagDescription{{Key: "flag1"}, {Key: "flag2"}}}
+		So(desc.Validate(opts), ShouldBeError, `a.go:3:33: unexpected arg key "key"`)
+
+		_, err = ParseOptions("flag;flag")
+		So(err, ShouldBeError, `already existed flag "flag"`)
+
+		dc, err := utils.MatchDeriveComment("// derive-set:  Order=Key  ")
+		So(err, ShouldBeNil)
+		So(dc.OptionsOffset, ShouldEqual, 16)
+	})
+}
+
+func TestOptionOrder(t *testing.T) {
+	Convey("option order", t, func() {
+		opts, err := ParseOptions("c;b=1;a")
+		So(err, ShouldBeNil)
+		So(opts.Order, ShouldResemble, []string{"c", "b", "a"})
+
+		another, err := ParseOptions("z;y=2;x")
+		So(err, ShouldBeNil)
+		So(opts.Merge(another), ShouldBeNil)
+		So(opts.Order, ShouldResemble, []string{"c", "b", "a", "z", "y", "x"})
+
+		desc := Description{ValidFlags: []FlagDescription{{Key: "a"}, {Key: "d"}}, AllowUnexpectedlyArg: true}
+		So(desc.Validate(opts), ShouldBeError, `unexpected flag []string{"c", "z", "x"}`)
+		So(opts.Order, ShouldResemble, []string{"c", "b", "a", "z", "y", "x", "d"})
+	})
+}
+
+func TestValidatePartial(t *testing.T) {
+	Convey("validate partial options", t, func() {
+		desc := Description{
+			ValidFlags: []FlagDescription{{Key: "a"}},
+			ValidArgs: []ArgDescription{
+				{Key: "b", ValidValues: NewValueSetFromSlice([]Value{"x", "y"})},
+				{Key: "c"},
+			},
+		}
+		opts, err := ParseOptions("b=x")
+		So(err, ShouldBeNil)
+		So(desc.ValidatePartial(opts), ShouldBeNil)
+		So(opts.Order, ShouldResemble, []string{"b"})
+
+		opts, err = ParseOptions("b=z")
+		So(err, ShouldBeNil)
+		So(desc.ValidatePartial(opts), ShouldBeError, `unsupported arg value "z"`)
+
+		opts, err = ParseOptions("a;d")
+		So(err, ShouldBeNil)
+		So(desc.ValidatePartial(opts), ShouldBeError, `unexpected flag "d"`)
+	})
+}
+
+func TestMergeDefault(t *testing.T) {
+	Convey("merge default options", t, func() {
+		opts, err := ParseOptions("a;b=1")
+		So(err, ShouldBeNil)
+		defaults, err := ParseOptions("!a;b=2;c=3;d")
+		So(err, ShouldBeNil)
+		opts.MergeDefault(defaults)
+		So(opts.Order, ShouldResemble, []string{"a", "b", "c", "d"})
+		So(opts.Flags[Flag("a")], ShouldEqual, utils.TriBoolTrue)
+		So(opts.Args["b"].Values, ShouldResemble, []Value{"1"})
+		So(opts.Args["c"].Values, ShouldResemble, []Value{"3"})
+		So(opts.Flags[Flag("d")], ShouldEqual, utils.TriBoolTrue)
+		So(opts.ExistingOption["c"], ShouldEqual, OptionTypeArgKey)
+	})
+}
+
+func TestOverride(t *testing.T) {
+	Convey("override options", t, func() {
+		opts, err := ParseOptionsAt("a;b=1;c", token.Position{Filename: "a.go", Line: 3, Column: 1})
+		So(err, ShouldBeNil)
+		forced, err := ParseOptions("!a;b=2;c=3;d")
+		So(err, ShouldBeNil)
+		opts.Override(forced)
+		So(opts.Order, ShouldResemble, []string{"a", "b", "c", "d"})
+		So(opts.Flags[Flag("a")], ShouldEqual, utils.TriBoolFalse)
+		So(opts.Args["b"].Values, ShouldResemble, []Value{"2"})
+		So(opts.Args["c"].Values, ShouldResemble, []Value{"3"})
+		So(opts.Flags, ShouldNotContainKey, Flag("c"))
+		So(opts.ExistingOption["c"], ShouldEqual, OptionTypeArgKey)
+		So(opts.Flags[Flag("d")], ShouldEqual, utils.TriBoolTrue)
+		So(opts.Positions, ShouldBeEmpty)
+		So(opts.String(), ShouldEqual, "!a;b=2;c=3;d")
+	})
+}
+
+func TestOptionsString(t *testing.T) {
+	Convey("options string", t, func() {
+		opts, err := ParseOptions(" Rename = Foo ; !Flag1; Order=Key ,Append;Flag2")
+		So(err, ShouldBeNil)
+		So(opts.String(), ShouldEqual, "Rename=Foo;!Flag1;Order=Key,Append;Flag2")
+
+		opts.SetFlag("Undefined", utils.TriBoolUndefined)
+		So(opts.String(), ShouldEqual, "Rename=Foo;!Flag1;Order=Key,Append;Flag2")
+		So(NewOptions().String(), ShouldBeEmpty)
+	})
+}
+
 func TestIdent(t *testing.T) {
 	Convey("ident validate", t, func() {
 		f := utils.ValidateIdentName
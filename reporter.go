@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nextzhou/goderive/utils"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// status of generated file of a package
+const (
+	StatusGenerated = "generated"
+	StatusUnchanged = "unchanged"
+	StatusStale     = "stale"
+	StatusDeleted   = "deleted"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+)
+
+type PackageResult struct {
+	Path   string `json:"path"`
+	Output string `json:"output"`
+	Status string `json:"status"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// RegenerationStatus is the status of a regeneration triggered by changes in watch mode.
+type RegenerationStatus struct {
+	Time     time.Time `json:"time"`
+	Packages []string  `json:"packages"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Reporter reports diagnostics and results of packages.
+type Reporter interface {
+	Diagnostic(err error, severity string)
+	Package(result PackageResult)
+	Regeneration(status RegenerationStatus)
+}
+
+func NewReporter(format string, stdout, stderr io.Writer) (Reporter, error) {
+	switch format {
+	case FormatText:
+		return &textReporter{stdout: stdout, stderr: stderr}, nil
+	case FormatJSON:
+		return &jsonReporter{encoder: json.NewEncoder(stdout)}, nil
+	default:
+		return nil, &utils.UnsupportedError{Type: "format", Idents: []string{format}}
+	}
+}
+
+type textReporter struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (r *textReporter) Diagnostic(err error, severity string) {
+	if severity == utils.SeverityError {
+		fmt.Fprintln(r.stderr, err)
+	} else {
+		fmt.Fprintf(r.stderr, "%s: %v\n", severity, err)
+	}
+}
+
+func (r *textReporter) Package(result PackageResult) {
+	// only stale, deleted and skipped files are interesting to humans
+	switch result.Status {
+	case StatusStale:
+		fmt.Fprintf(r.stdout, "stale: %s\n", result.Output)
+		fmt.Fprint(r.stdout, result.Diff)
+	case StatusDeleted:
+		fmt.Fprintf(r.stdout, "deleted: %s\n", result.Output)
+	case StatusSkipped:
+		fmt.Fprintf(r.stdout, "skipped: %s\n", result.Output)
+	}
+}
+
+func (r *textReporter) Regeneration(status RegenerationStatus) {
+	packages := strings.Join(status.Packages, ", ")
+	if status.Error != "" {
+		fmt.Fprintf(r.stdout, "%s failed to regenerate %s: %s\n", status.Time.Format("15:04:05"), packages, status.Error)
+	} else {
+		fmt.Fprintf(r.stdout, "%s regenerated %s\n", status.Time.Format("15:04:05"), packages)
+	}
+}
+
+// jsonReporter writes a JSON record per line
+type jsonReporter struct {
+	encoder *json.Encoder
+}
+
+func (r *jsonReporter) Diagnostic(err error, severity string) {
+	diag := utils.MakeDiagnostic(err, severity)
+	r.encoder.Encode(struct {
+		Kind string `json:"kind"`
+		*utils.Diagnostic
+	}{Kind: "diagnostic", Diagnostic: &diag})
+}
+
+func (r *jsonReporter) Package(result PackageResult) {
+	r.encoder.Encode(struct {
+		Kind string `json:"kind"`
+		*PackageResult
+	}{Kind: "package", PackageResult: &result})
+}
+
+func (r *jsonReporter) Regeneration(status RegenerationStatus) {
+	r.encoder.Encode(struct {
+		Kind string `json:"kind"`
+		*RegenerationStatus
+	}{Kind: "regeneration", RegenerationStatus: &status})
+}
@@ -13,38 +13,6 @@ import (
 	"github.com/nextzhou/goderive/plugin"
 )
 
-func (a *AA) getB() *b {
-	if a == nil {
-		var defaultVal *b
-		return defaultVal
-	}
-	return a.b
-}
-
-func (a *AA) GetB() *b {
-	if a == nil {
-		var defaultVal *b
-		return defaultVal
-	}
-	return a.B
-}
-
-func (a *AA) getC() *c {
-	if a == nil {
-		var defaultVal *c
-		return defaultVal
-	}
-	return a.c
-}
-
-func (a *AA) GetC() *c {
-	if a == nil {
-		var defaultVal *c
-		return defaultVal
-	}
-	return a.C
-}
-
 type IntSet struct {
 	elements map[int]struct{}
 }
@@ -1920,71 +1888,71 @@ func (set *Int3Set) UnmarshalJSON(b []byte) error {
 	return fmt.Errorf("unsupported")
 }
 
-type myTypeSet struct {
-	elements map[MyType]struct{}
+type hSet struct {
+	elements map[http.Handler]struct{}
 }
 
-func newMyTypeSet(capacity int) *myTypeSet {
-	set := new(myTypeSet)
+func newHSet(capacity int) *hSet {
+	set := new(hSet)
 	if capacity > 0 {
-		set.elements = make(map[MyType]struct{}, capacity)
+		set.elements = make(map[http.Handler]struct{}, capacity)
 	} else {
-		set.elements = make(map[MyType]struct{})
+		set.elements = make(map[http.Handler]struct{})
 	}
 	return set
 }
 
-func newMyTypeSetFromSlice(items []MyType) *myTypeSet {
-	set := newMyTypeSet(len(items))
+func newHSetFromSlice(items []http.Handler) *hSet {
+	set := newHSet(len(items))
 	for _, item := range items {
 		set.Append(item)
 	}
 	return set
 }
 
-func (set *myTypeSet) Len() int {
+func (set *hSet) Len() int {
 	if set == nil {
 		return 0
 	}
 	return len(set.elements)
 }
 
-func (set *myTypeSet) IsEmpty() bool {
+func (set *hSet) IsEmpty() bool {
 	return set.Len() == 0
 }
 
-func (set *myTypeSet) ToSlice() []MyType {
+func (set *hSet) ToSlice() []http.Handler {
 	if set == nil {
 		return nil
 	}
-	s := make([]MyType, 0, set.Len())
-	set.ForEach(func(item MyType) {
+	s := make([]http.Handler, 0, set.Len())
+	set.ForEach(func(item http.Handler) {
 		s = append(s, item)
 	})
 	return s
 }
 
-func (set *myTypeSet) Append(keys ...MyType) {
+func (set *hSet) Append(keys ...http.Handler) {
 	for _, key := range keys {
 		set.elements[key] = struct{}{}
 	}
 }
 
-func (set *myTypeSet) Clear() {
-	set.elements = make(map[MyType]struct{})
+func (set *hSet) Clear() {
+	set.elements = make(map[http.Handler]struct{})
 }
 
-func (set *myTypeSet) Clone() *myTypeSet {
-	cloned := newMyTypeSet(set.Len())
+func (set *hSet) Clone() *hSet {
+	cloned := newHSet(set.Len())
 	for item := range set.elements {
 		cloned.elements[item] = struct{}{}
 	}
 	return cloned
 }
 
-func (set *myTypeSet) Difference(another *myTypeSet) *myTypeSet {
-	difference := newMyTypeSet(0)
-	set.ForEach(func(item MyType) {
+func (set *hSet) Difference(another *hSet) *hSet {
+	difference := newHSet(0)
+	set.ForEach(func(item http.Handler) {
 		if !another.Contains(item) {
 			difference.Append(item)
 		}
@@ -1992,7 +1960,7 @@ func (set *myTypeSet) Difference(another *myTypeSet) *myTypeSet {
 	return difference
 }
 
-func (set *myTypeSet) Equal(another *myTypeSet) bool {
+func (set *hSet) Equal(another *hSet) bool {
 	if set.Len() != another.Len() {
 		return false
 	}
@@ -2004,8 +1972,8 @@ func (set *myTypeSet) Equal(another *myTypeSet) bool {
 	return true
 }
 
-func (set *myTypeSet) Intersect(another *myTypeSet) *myTypeSet {
-	intersection := newMyTypeSet(0)
+func (set *hSet) Intersect(another *hSet) *hSet {
+	intersection := newHSet(0)
 	if set.Len() < another.Len() {
 		for item := range set.elements {
 			if another.Contains(item) {
@@ -2022,27 +1990,27 @@ func (set *myTypeSet) Intersect(another *myTypeSet) *myTypeSet {
 	return intersection
 }
 
-func (set *myTypeSet) Union(another *myTypeSet) *myTypeSet {
+func (set *hSet) Union(another *hSet) *hSet {
 	union := set.Clone()
 	union.InPlaceUnion(another)
 	return union
 }
 
-func (set *myTypeSet) InPlaceUnion(another *myTypeSet) {
-	another.ForEach(func(item MyType) {
+func (set *hSet) InPlaceUnion(another *hSet) {
+	another.ForEach(func(item http.Handler) {
 		set.Append(item)
 	})
 }
 
-func (set *myTypeSet) IsProperSubsetOf(another *myTypeSet) bool {
+func (set *hSet) IsProperSubsetOf(another *hSet) bool {
 	return !set.Equal(another) && set.IsSubsetOf(another)
 }
 
-func (set *myTypeSet) IsProperSupersetOf(another *myTypeSet) bool {
+func (set *hSet) IsProperSupersetOf(another *hSet) bool {
 	return !set.Equal(another) && set.IsSupersetOf(another)
 }
 
-func (set *myTypeSet) IsSubsetOf(another *myTypeSet) bool {
+func (set *hSet) IsSubsetOf(another *hSet) bool {
 	if set.Len() > another.Len() {
 		return false
 	}
@@ -2054,11 +2022,11 @@ func (set *myTypeSet) IsSubsetOf(another *myTypeSet) bool {
 	return true
 }
 
-func (set *myTypeSet) IsSupersetOf(another *myTypeSet) bool {
+func (set *hSet) IsSupersetOf(another *hSet) bool {
 	return another.IsSubsetOf(set)
 }
 
-func (set *myTypeSet) ForEach(f func(MyType)) {
+func (set *hSet) ForEach(f func(http.Handler)) {
 	if set.IsEmpty() {
 		return
 	}
@@ -2067,9 +2035,9 @@ func (set *myTypeSet) ForEach(f func(MyType)) {
 	}
 }
 
-func (set *myTypeSet) Filter(f func(MyType) bool) *myTypeSet {
-	result := newMyTypeSet(0)
-	set.ForEach(func(item MyType) {
+func (set *hSet) Filter(f func(http.Handler) bool) *hSet {
+	result := newHSet(0)
+	set.ForEach(func(item http.Handler) {
 		if f(item) {
 			result.Append(item)
 		}
@@ -2077,16 +2045,16 @@ func (set *myTypeSet) Filter(f func(MyType) bool) *myTypeSet {
 	return result
 }
 
-func (set *myTypeSet) Remove(key MyType) {
+func (set *hSet) Remove(key http.Handler) {
 	delete(set.elements, key)
 }
 
-func (set *myTypeSet) Contains(key MyType) bool {
+func (set *hSet) Contains(key http.Handler) bool {
 	_, ok := set.elements[key]
 	return ok
 }
 
-func (set *myTypeSet) ContainsAny(keys ...MyType) bool {
+func (set *hSet) ContainsAny(keys ...http.Handler) bool {
 	for _, key := range keys {
 		if set.Contains(key) {
 			return true
@@ -2095,7 +2063,7 @@ func (set *myTypeSet) ContainsAny(keys ...MyType) bool {
 	return false
 }
 
-func (set *myTypeSet) ContainsAll(keys ...MyType) bool {
+func (set *hSet) ContainsAll(keys ...http.Handler) bool {
 	for _, key := range keys {
 		if !set.Contains(key) {
 			return false
@@ -2104,7 +2072,7 @@ func (set *myTypeSet) ContainsAll(keys ...MyType) bool {
 	return true
 }
 
-func (set *myTypeSet) DoUntilError(f func(MyType) error) error {
+func (set *hSet) DoUntilError(f func(http.Handler) error) error {
 	for item := range set.elements {
 		if err := f(item); err != nil {
 			return err
@@ -2113,7 +2081,7 @@ func (set *myTypeSet) DoUntilError(f func(MyType) error) error {
 	return nil
 }
 
-func (set *myTypeSet) All(f func(MyType) bool) bool {
+func (set *hSet) All(f func(http.Handler) bool) bool {
 	for item := range set.elements {
 		if !f(item) {
 			return false
@@ -2122,7 +2090,7 @@ func (set *myTypeSet) All(f func(MyType) bool) bool {
 	return true
 }
 
-func (set *myTypeSet) Any(f func(MyType) bool) bool {
+func (set *hSet) Any(f func(http.Handler) bool) bool {
 	for item := range set.elements {
 		if f(item) {
 			return true
@@ -2131,7 +2099,7 @@ func (set *myTypeSet) Any(f func(MyType) bool) bool {
 	return false
 }
 
-func (set *myTypeSet) FindBy(f func(MyType) bool) *MyType {
+func (set *hSet) FindBy(f func(http.Handler) bool) *http.Handler {
 	for item := range set.elements {
 		if f(item) {
 			return &item
@@ -2140,9 +2108,9 @@ func (set *myTypeSet) FindBy(f func(MyType) bool) *MyType {
 	return nil
 }
 
-func (set *myTypeSet) CountBy(f func(MyType) bool) int {
+func (set *hSet) CountBy(f func(http.Handler) bool) int {
 	count := 0
-	set.ForEach(func(item MyType) {
+	set.ForEach(func(item http.Handler) {
 		if f(item) {
 			count++
 		}
@@ -2150,9 +2118,9 @@ func (set *myTypeSet) CountBy(f func(MyType) bool) int {
 	return count
 }
 
-func (set *myTypeSet) GroupByBool(f func(MyType) bool) (trueGroup *myTypeSet, falseGroup *myTypeSet) {
-	trueGroup, falseGroup = newMyTypeSet(0), newMyTypeSet(0)
-	set.ForEach(func(item MyType) {
+func (set *hSet) GroupByBool(f func(http.Handler) bool) (trueGroup *hSet, falseGroup *hSet) {
+	trueGroup, falseGroup = newHSet(0), newHSet(0)
+	set.ForEach(func(item http.Handler) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -2162,13 +2130,13 @@ func (set *myTypeSet) GroupByBool(f func(MyType) bool) (trueGroup *myTypeSet, fa
 	return trueGroup, falseGroup
 }
 
-func (set *myTypeSet) GroupByStr(f func(MyType) string) map[string]*myTypeSet {
-	groups := make(map[string]*myTypeSet)
-	set.ForEach(func(item MyType) {
+func (set *hSet) GroupByStr(f func(http.Handler) string) map[string]*hSet {
+	groups := make(map[string]*hSet)
+	set.ForEach(func(item http.Handler) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newMyTypeSet(0)
+			group = newHSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -2176,13 +2144,13 @@ func (set *myTypeSet) GroupByStr(f func(MyType) string) map[string]*myTypeSet {
 	return groups
 }
 
-func (set *myTypeSet) GroupByInt(f func(MyType) int) map[int]*myTypeSet {
-	groups := make(map[int]*myTypeSet)
-	set.ForEach(func(item MyType) {
+func (set *hSet) GroupByInt(f func(http.Handler) int) map[int]*hSet {
+	groups := make(map[int]*hSet)
+	set.ForEach(func(item http.Handler) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newMyTypeSet(0)
+			group = newHSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -2190,13 +2158,13 @@ func (set *myTypeSet) GroupByInt(f func(MyType) int) map[int]*myTypeSet {
 	return groups
 }
 
-func (set *myTypeSet) GroupBy(f func(MyType) interface{}) map[interface{}]*myTypeSet {
-	groups := make(map[interface{}]*myTypeSet)
-	set.ForEach(func(item MyType) {
+func (set *hSet) GroupBy(f func(http.Handler) interface{}) map[interface{}]*hSet {
+	groups := make(map[interface{}]*hSet)
+	set.ForEach(func(item http.Handler) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newMyTypeSet(0)
+			group = newHSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -2204,10 +2172,10 @@ func (set *myTypeSet) GroupBy(f func(MyType) interface{}) map[interface{}]*myTyp
 	return groups
 }
 
-// f: func(MyType) T
+// f: func(http.Handler) T
 // return: []T
-func (set *myTypeSet) Map(f interface{}) interface{} {
-	expected := "f should be func(MyType)T"
+func (set *hSet) Map(f interface{}) interface{} {
+	expected := "f should be func(http.Handler)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -2216,7 +2184,7 @@ func (set *myTypeSet) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(MyType)).Elem()
+	elemType := reflect.TypeOf(new(http.Handler)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -2225,18 +2193,18 @@ func (set *myTypeSet) Map(f interface{}) interface{} {
 	}
 	outType := ft.Out(0)
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item MyType) {
+	set.ForEach(func(item http.Handler) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(MyType) *T
-//    func(MyType) (T, bool)
-//    func(MyType) (T, error)
+// f: func(http.Handler) *T
+//    func(http.Handler) (T, bool)
+//    func(http.Handler) (T, error)
 // return: []T
-func (set *myTypeSet) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(MyType) *T / func(MyType) (T, bool) / func(MyType) (T, error)"
+func (set *hSet) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(http.Handler) *T / func(http.Handler) (T, bool) / func(http.Handler) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -2246,13 +2214,13 @@ func (set *myTypeSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(MyType)).Elem() {
+	if in != reflect.TypeOf(new(http.Handler)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(MyType) *T
+		// func(http.Handler) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -2269,7 +2237,7 @@ func (set *myTypeSet) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(MyType) (T, bool)
+			// func(http.Handler) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -2277,7 +2245,7 @@ func (set *myTypeSet) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(MyType) (T, error)
+			// func(http.Handler) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -2292,7 +2260,7 @@ func (set *myTypeSet) FilterMap(f interface{}) interface{} {
 	}
 
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item MyType) {
+	set.ForEach(func(item http.Handler) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -2301,12 +2269,12 @@ func (set *myTypeSet) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (set *myTypeSet) Reduce(f func(MyType, MyType) MyType) MyType {
+func (set *hSet) Reduce(f func(http.Handler, http.Handler) http.Handler) http.Handler {
 	if set.IsEmpty() {
-		var defaultVal MyType
+		var defaultVal http.Handler
 		return defaultVal
 	}
-	var ret MyType
+	var ret http.Handler
 	first := true
 	for item := range set.elements {
 		if first {
@@ -2319,7 +2287,7 @@ func (set *myTypeSet) Reduce(f func(MyType, MyType) MyType) MyType {
 	return ret
 }
 
-func (set *myTypeSet) Fold(init MyType, f func(MyType, MyType) MyType) MyType {
+func (set *hSet) Fold(init http.Handler, f func(http.Handler, http.Handler) http.Handler) http.Handler {
 	if set.IsEmpty() {
 		return init
 	}
@@ -2329,84 +2297,96 @@ func (set *myTypeSet) Fold(init MyType, f func(MyType, MyType) MyType) MyType {
 	return init
 }
 
-func (set *myTypeSet) String() string {
+func (set *hSet) String() string {
 	return fmt.Sprint(set.ToSlice())
 }
 
-func (set myTypeSet) MarshalJSON() ([]byte, error) {
+func (set hSet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(set.ToSlice())
 }
 
-func (set *myTypeSet) UnmarshalJSON(b []byte) error {
-	s := make([]MyType, 0)
+func (set *hSet) UnmarshalJSON(b []byte) error {
+	s := make([]http.Handler, 0)
 	err := json.Unmarshal(b, &s)
 	if err != nil {
 		return err
 	}
-	*set = *newMyTypeSetFromSlice(s)
+	*set = *newHSetFromSlice(s)
 	return nil
 }
 
-type NotComparableTypeSlice struct {
-	elements []NotComparableType
+type hSlice struct {
+	elements []http.Handler
 }
 
-func NewNotComparableTypeSlice(capacity int) *NotComparableTypeSlice {
-	return &NotComparableTypeSlice{
-		elements: make([]NotComparableType, 0, capacity),
+func newHSlice(capacity int) *hSlice {
+	return &hSlice{
+		elements: make([]http.Handler, 0, capacity),
 	}
 }
 
-func NewNotComparableTypeSliceFromSlice(slice []NotComparableType) *NotComparableTypeSlice {
-	return &NotComparableTypeSlice{
+func newHSliceFromSlice(slice []http.Handler) *hSlice {
+	return &hSlice{
 		elements: slice,
 	}
 }
 
-func (s *NotComparableTypeSlice) Len() int {
+func (s *hSlice) Len() int {
 	if s == nil {
 		return 0
 	}
 	return len(s.elements)
 }
 
-func (s *NotComparableTypeSlice) IsEmpty() bool {
+func (s *hSlice) IsEmpty() bool {
 	return s.Len() == 0
 }
 
-func (s *NotComparableTypeSlice) Append(items ...NotComparableType) {
+func (s *hSlice) Append(items ...http.Handler) {
 	s.elements = append(s.elements, items...)
 }
 
-func (s *NotComparableTypeSlice) Clone() *NotComparableTypeSlice {
-	cloned := &NotComparableTypeSlice{
-		elements: make([]NotComparableType, s.Len()),
+func (s *hSlice) Clone() *hSlice {
+	cloned := &hSlice{
+		elements: make([]http.Handler, s.Len()),
 	}
 	copy(cloned.elements, s.elements)
 	return cloned
 }
 
-func (s *NotComparableTypeSlice) ToSlice() []NotComparableType {
-	slice := make([]NotComparableType, s.Len())
+func (s *hSlice) ToSlice() []http.Handler {
+	slice := make([]http.Handler, s.Len())
 	copy(slice, s.elements)
 	return slice
 }
 
-func (s *NotComparableTypeSlice) ToSliceRef() []NotComparableType {
+func (s *hSlice) ToSliceRef() []http.Handler {
 	return s.elements
 }
 
-func (s *NotComparableTypeSlice) Clear() {
+func (s *hSlice) Clear() {
 	s.elements = s.elements[:0]
 }
 
-func (s *NotComparableTypeSlice) Insert(idx int, items ...NotComparableType) {
+func (s *hSlice) Equal(another *hSlice) bool {
+	if s.Len() != another.Len() {
+		return false
+	}
+	for idx, item := range s.elements {
+		if item != another.elements[idx] {
+			return false
+		}
+	}
+	return false
+}
+
+func (s *hSlice) Insert(idx int, items ...http.Handler) {
 	if idx < 0 {
 		idx += s.Len()
 	}
 	if l := len(s.elements) + len(items); l > cap(s.elements) {
 		// reallocate
-		result := make([]NotComparableType, l)
+		result := make([]http.Handler, l)
 		copy(result, s.elements[:idx])
 		copy(result[idx:], items)
 		copy(result[idx+len(items):], s.elements[idx:])
@@ -2420,14 +2400,14 @@ func (s *NotComparableTypeSlice) Insert(idx int, items ...NotComparableType) {
 	copy(s.elements[idx:], items)
 }
 
-func (s *NotComparableTypeSlice) Remove(idx int) {
+func (s *hSlice) Remove(idx int) {
 	if idx < 0 {
 		idx += s.Len()
 	}
 	s.elements = append(s.elements[:idx], s.elements[idx+1:]...)
 }
 
-func (s *NotComparableTypeSlice) RemoveRange(from, to int) {
+func (s *hSlice) RemoveRange(from, to int) {
 	if from < 0 {
 		from += s.Len()
 	}
@@ -2437,21 +2417,21 @@ func (s *NotComparableTypeSlice) RemoveRange(from, to int) {
 	s.elements = append(s.elements[:from], s.elements[to+1:]...)
 }
 
-func (s *NotComparableTypeSlice) RemoveFrom(idx int) {
+func (s *hSlice) RemoveFrom(idx int) {
 	if idx < 0 {
 		idx += s.Len()
 	}
 	s.elements = s.elements[:idx]
 }
 
-func (s *NotComparableTypeSlice) RemoveTo(idx int) {
+func (s *hSlice) RemoveTo(idx int) {
 	if idx < 0 {
 		idx += s.Len()
 	}
 	s.elements = s.elements[idx+1:]
 }
 
-func (s *NotComparableTypeSlice) Concat(another *NotComparableTypeSlice) *NotComparableTypeSlice {
+func (s *hSlice) Concat(another *hSlice) *hSlice {
 	result := s.Clone()
 	if another.IsEmpty() {
 		return result
@@ -2460,14 +2440,14 @@ func (s *NotComparableTypeSlice) Concat(another *NotComparableTypeSlice) *NotCom
 	return result
 }
 
-func (s *NotComparableTypeSlice) InPlaceConcat(another *NotComparableTypeSlice) {
+func (s *hSlice) InPlaceConcat(another *hSlice) {
 	if another.IsEmpty() {
 		return
 	}
 	s.Append(another.elements...)
 }
 
-func (s *NotComparableTypeSlice) ForEach(f func(NotComparableType)) {
+func (s *hSlice) ForEach(f func(http.Handler)) {
 	if s.IsEmpty() {
 		return
 	}
@@ -2476,7 +2456,7 @@ func (s *NotComparableTypeSlice) ForEach(f func(NotComparableType)) {
 	}
 }
 
-func (s *NotComparableTypeSlice) ForEachWithIndex(f func(int, NotComparableType)) {
+func (s *hSlice) ForEachWithIndex(f func(int, http.Handler)) {
 	if s.IsEmpty() {
 		return
 	}
@@ -2485,8 +2465,8 @@ func (s *NotComparableTypeSlice) ForEachWithIndex(f func(int, NotComparableType)
 	}
 }
 
-func (s *NotComparableTypeSlice) Filter(f func(NotComparableType) bool) *NotComparableTypeSlice {
-	result := NewNotComparableTypeSlice(0)
+func (s *hSlice) Filter(f func(http.Handler) bool) *hSlice {
+	result := newHSlice(0)
 	for _, item := range s.elements {
 		if f(item) {
 			result.Append(item)
@@ -2495,38 +2475,59 @@ func (s *NotComparableTypeSlice) Filter(f func(NotComparableType) bool) *NotComp
 	return result
 }
 
-func (s *NotComparableTypeSlice) Index(idx int) *NotComparableType {
+func (s *hSlice) Index(idx int) *http.Handler {
 	if idx < 0 {
 		idx += s.Len()
 	}
 	return &s.elements[idx]
 }
 
-func (s *NotComparableTypeSlice) IndexRange(from, to int) *NotComparableTypeSlice {
+func (s *hSlice) IndexRange(from, to int) *hSlice {
 	if from < 0 {
 		from += s.Len()
 	}
 	if to < 0 {
 		to += s.Len()
 	}
-	return NewNotComparableTypeSliceFromSlice(s.elements[from:to])
+	return newHSliceFromSlice(s.elements[from:to])
 }
 
-func (s *NotComparableTypeSlice) IndexFrom(idx int) *NotComparableTypeSlice {
+func (s *hSlice) IndexFrom(idx int) *hSlice {
 	if idx < 0 {
 		idx += s.Len()
 	}
-	return NewNotComparableTypeSliceFromSlice(s.elements[idx:])
+	return newHSliceFromSlice(s.elements[idx:])
 }
 
-func (s *NotComparableTypeSlice) IndexTo(idx int) *NotComparableTypeSlice {
+func (s *hSlice) IndexTo(idx int) *hSlice {
 	if idx < 0 {
 		idx += s.Len()
 	}
-	return NewNotComparableTypeSliceFromSlice(s.elements[:idx])
+	return newHSliceFromSlice(s.elements[:idx])
 }
 
-func (s *NotComparableTypeSlice) FindBy(f func(NotComparableType) bool) int {
+func (s *hSlice) Find(item http.Handler) int {
+	if s.IsEmpty() {
+		return -1
+	}
+	for idx, n := range s.elements {
+		if n == item {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (s *hSlice) FindLast(item http.Handler) int {
+	for idx := s.Len() - 1; idx >= 0; idx-- {
+		if s.elements[idx] == item {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (s *hSlice) FindBy(f func(http.Handler) bool) int {
 	if s.IsEmpty() {
 		return -1
 	}
@@ -2538,7 +2539,7 @@ func (s *NotComparableTypeSlice) FindBy(f func(NotComparableType) bool) int {
 	return -1
 }
 
-func (s *NotComparableTypeSlice) FindLastBy(f func(NotComparableType) bool) int {
+func (s *hSlice) FindLastBy(f func(http.Handler) bool) int {
 	for idx := s.Len() - 1; idx >= 0; idx-- {
 		if f(s.elements[idx]) {
 			return idx
@@ -2547,9 +2548,19 @@ func (s *NotComparableTypeSlice) FindLastBy(f func(NotComparableType) bool) int
 	return -1
 }
 
-func (s *NotComparableTypeSlice) CountBy(f func(NotComparableType) bool) uint {
+func (s *hSlice) Count(item http.Handler) uint {
 	count := uint(0)
-	s.ForEach(func(item NotComparableType) {
+	s.ForEach(func(n http.Handler) {
+		if n == item {
+			count++
+		}
+	})
+	return count
+}
+
+func (s *hSlice) CountBy(f func(http.Handler) bool) uint {
+	count := uint(0)
+	s.ForEach(func(item http.Handler) {
 		if f(item) {
 			count++
 		}
@@ -2557,9 +2568,9 @@ func (s *NotComparableTypeSlice) CountBy(f func(NotComparableType) bool) uint {
 	return count
 }
 
-func (s *NotComparableTypeSlice) GroupByBool(f func(NotComparableType) bool) (trueGroup, falseGroup *NotComparableTypeSlice) {
-	trueGroup, falseGroup = NewNotComparableTypeSlice(0), NewNotComparableTypeSlice(0)
-	s.ForEach(func(item NotComparableType) {
+func (s *hSlice) GroupByBool(f func(http.Handler) bool) (trueGroup, falseGroup *hSlice) {
+	trueGroup, falseGroup = newHSlice(0), newHSlice(0)
+	s.ForEach(func(item http.Handler) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -2569,13 +2580,13 @@ func (s *NotComparableTypeSlice) GroupByBool(f func(NotComparableType) bool) (tr
 	return trueGroup, falseGroup
 }
 
-func (s NotComparableTypeSlice) GroupByStr(f func(NotComparableType) string) map[string]*NotComparableTypeSlice {
-	groups := make(map[string]*NotComparableTypeSlice)
-	s.ForEach(func(item NotComparableType) {
+func (s hSlice) GroupByStr(f func(http.Handler) string) map[string]*hSlice {
+	groups := make(map[string]*hSlice)
+	s.ForEach(func(item http.Handler) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewNotComparableTypeSlice(0)
+			group = newHSlice(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -2583,13 +2594,13 @@ func (s NotComparableTypeSlice) GroupByStr(f func(NotComparableType) string) map
 	return groups
 }
 
-func (s NotComparableTypeSlice) GroupByInt(f func(NotComparableType) int) map[int]*NotComparableTypeSlice {
-	groups := make(map[int]*NotComparableTypeSlice)
-	s.ForEach(func(item NotComparableType) {
+func (s hSlice) GroupByInt(f func(http.Handler) int) map[int]*hSlice {
+	groups := make(map[int]*hSlice)
+	s.ForEach(func(item http.Handler) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewNotComparableTypeSlice(0)
+			group = newHSlice(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -2597,13 +2608,13 @@ func (s NotComparableTypeSlice) GroupByInt(f func(NotComparableType) int) map[in
 	return groups
 }
 
-func (s *NotComparableTypeSlice) GroupBy(f func(NotComparableType) interface{}) map[interface{}]*NotComparableTypeSlice {
-	groups := make(map[interface{}]*NotComparableTypeSlice)
-	s.ForEach(func(item NotComparableType) {
+func (s *hSlice) GroupBy(f func(http.Handler) interface{}) map[interface{}]*hSlice {
+	groups := make(map[interface{}]*hSlice)
+	s.ForEach(func(item http.Handler) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewNotComparableTypeSlice(0)
+			group = newHSlice(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -2611,10 +2622,10 @@ func (s *NotComparableTypeSlice) GroupBy(f func(NotComparableType) interface{})
 	return groups
 }
 
-// f: func(NotComparableType) T
+// f: func(http.Handler) T
 // return: []T
-func (s *NotComparableTypeSlice) Map(f interface{}) interface{} {
-	expected := "f should be func(NotComparableType)T"
+func (s *hSlice) Map(f interface{}) interface{} {
+	expected := "f should be func(http.Handler)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -2623,7 +2634,7 @@ func (s *NotComparableTypeSlice) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(NotComparableType)).Elem()
+	elemType := reflect.TypeOf(new(http.Handler)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -2632,18 +2643,18 @@ func (s *NotComparableTypeSlice) Map(f interface{}) interface{} {
 	}
 	outType := ft.Out(0)
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
-	s.ForEach(func(item NotComparableType) {
+	s.ForEach(func(item http.Handler) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(NotComparableType) *T
-//    func(NotComparableType) (T, bool)
-//    func(NotComparableType) (T, error)
+// f: func(http.Handler) *T
+//    func(http.Handler) (T, bool)
+//    func(http.Handler) (T, error)
 // return: []T
-func (s *NotComparableTypeSlice) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(NotComparableType) *T / func(NotComparableType) (T, bool) / func(NotComparableType) (T, error)"
+func (s *hSlice) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(http.Handler) *T / func(http.Handler) (T, bool) / func(http.Handler) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -2653,13 +2664,13 @@ func (s *NotComparableTypeSlice) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(NotComparableType)).Elem() {
+	if in != reflect.TypeOf(new(http.Handler)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(NotComparableType) *T
+		// func(http.Handler) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -2676,7 +2687,7 @@ func (s *NotComparableTypeSlice) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(NotComparableType) (T, bool)
+			// func(http.Handler) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -2684,7 +2695,7 @@ func (s *NotComparableTypeSlice) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(NotComparableType) (T, error)
+			// func(http.Handler) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -2699,7 +2710,7 @@ func (s *NotComparableTypeSlice) FilterMap(f interface{}) interface{} {
 	}
 
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
-	s.ForEach(func(item NotComparableType) {
+	s.ForEach(func(item http.Handler) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -2708,7 +2719,7 @@ func (s *NotComparableTypeSlice) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (s *NotComparableTypeSlice) DoUntil(f func(NotComparableType) bool) int {
+func (s *hSlice) DoUntil(f func(http.Handler) bool) int {
 	for idx, item := range s.elements {
 		if f(item) {
 			return idx
@@ -2717,7 +2728,7 @@ func (s *NotComparableTypeSlice) DoUntil(f func(NotComparableType) bool) int {
 	return -1
 }
 
-func (s *NotComparableTypeSlice) DoWhile(f func(NotComparableType) bool) int {
+func (s *hSlice) DoWhile(f func(http.Handler) bool) int {
 	for idx, item := range s.elements {
 		if !f(item) {
 			return idx
@@ -2726,7 +2737,7 @@ func (s *NotComparableTypeSlice) DoWhile(f func(NotComparableType) bool) int {
 	return -1
 }
 
-func (s *NotComparableTypeSlice) DoUntilError(f func(NotComparableType) error) error {
+func (s *hSlice) DoUntilError(f func(http.Handler) error) error {
 	for _, item := range s.elements {
 		if err := f(item); err != nil {
 			return err
@@ -2735,7 +2746,7 @@ func (s *NotComparableTypeSlice) DoUntilError(f func(NotComparableType) error) e
 	return nil
 }
 
-func (s *NotComparableTypeSlice) All(f func(NotComparableType) bool) bool {
+func (s *hSlice) All(f func(http.Handler) bool) bool {
 	for _, item := range s.elements {
 		if !f(item) {
 			return false
@@ -2744,7 +2755,7 @@ func (s *NotComparableTypeSlice) All(f func(NotComparableType) bool) bool {
 	return true
 }
 
-func (s *NotComparableTypeSlice) Any(f func(NotComparableType) bool) bool {
+func (s *hSlice) Any(f func(http.Handler) bool) bool {
 	for _, item := range s.elements {
 		if f(item) {
 			return true
@@ -2753,9 +2764,9 @@ func (s *NotComparableTypeSlice) Any(f func(NotComparableType) bool) bool {
 	return false
 }
 
-func (s *NotComparableTypeSlice) Reduce(f func(NotComparableType, NotComparableType) NotComparableType) NotComparableType {
+func (s *hSlice) Reduce(f func(http.Handler, http.Handler) http.Handler) http.Handler {
 	if s.IsEmpty() {
-		var defaultVal NotComparableType
+		var defaultVal http.Handler
 		return defaultVal
 	}
 	ret := s.elements[0]
@@ -2765,7 +2776,7 @@ func (s *NotComparableTypeSlice) Reduce(f func(NotComparableType, NotComparableT
 	return ret
 }
 
-func (s *NotComparableTypeSlice) Fold(init NotComparableType, f func(NotComparableType, NotComparableType) NotComparableType) NotComparableType {
+func (s *hSlice) Fold(init http.Handler, f func(http.Handler, http.Handler) http.Handler) http.Handler {
 	if s.IsEmpty() {
 		return init
 	}
@@ -2775,121 +2786,83 @@ func (s *NotComparableTypeSlice) Fold(init NotComparableType, f func(NotComparab
 	return init
 }
 
-func (s *NotComparableTypeSlice) String() string {
+func (s *hSlice) String() string {
 	return fmt.Sprint(s.elements)
 }
 
-func (s NotComparableTypeSlice) MarshalJSON() ([]byte, error) {
+func (s hSlice) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.elements)
 }
 
-func (s *NotComparableTypeSlice) UnmarshalJSON(b []byte) error {
+func (s *hSlice) UnmarshalJSON(b []byte) error {
 	return json.Unmarshal(b, &s.elements)
 }
 
-type SSet struct {
-	cmp             func(i, j string) bool
-	elements        map[string]uint32
-	elementSequence []string
+type TSet struct {
+	elements map[t.Time]struct{}
 }
 
-func NewSSet(capacity int, cmp func(i, j string) bool) *SSet {
-	set := new(SSet)
+func NewTSet(capacity int) *TSet {
+	set := new(TSet)
 	if capacity > 0 {
-		set.elements = make(map[string]uint32, capacity)
-		set.elementSequence = make([]string, 0, capacity)
+		set.elements = make(map[t.Time]struct{}, capacity)
 	} else {
-		set.elements = make(map[string]uint32)
+		set.elements = make(map[t.Time]struct{})
 	}
-	set.cmp = cmp
 	return set
 }
 
-func NewSSetFromSlice(items []string, cmp func(i, j string) bool) *SSet {
-	set := NewSSet(len(items), cmp)
+func NewTSetFromSlice(items []t.Time) *TSet {
+	set := NewTSet(len(items))
 	for _, item := range items {
 		set.Append(item)
 	}
 	return set
 }
 
-func NewAscendingSSet(capacity int) *SSet {
-	return NewSSet(capacity, func(i, j string) bool { return i < j })
-}
-
-func NewDescendingSSet(capacity int) *SSet {
-	return NewSSet(capacity, func(i, j string) bool { return i > j })
-}
-
-func NewAscendingSSetFromSlice(items []string) *SSet {
-	return NewSSetFromSlice(items, func(i, j string) bool { return i < j })
-}
-
-func NewDescendingSSetFromSlice(items []string) *SSet {
-	return NewSSetFromSlice(items, func(i, j string) bool { return i > j })
-}
-
-func (set *SSet) Len() int {
+func (set *TSet) Len() int {
 	if set == nil {
 		return 0
 	}
 	return len(set.elements)
 }
 
-func (set *SSet) IsEmpty() bool {
+func (set *TSet) IsEmpty() bool {
 	return set.Len() == 0
 }
 
-func (set *SSet) ToSlice() []string {
+func (set *TSet) ToSlice() []t.Time {
 	if set == nil {
 		return nil
 	}
-	s := make([]string, set.Len())
-	copy(s, set.elementSequence)
+	s := make([]t.Time, 0, set.Len())
+	set.ForEach(func(item t.Time) {
+		s = append(s, item)
+	})
 	return s
 }
 
-// NOTICE: efficient but unsafe
-func (set *SSet) ToSliceRef() []string {
-	return set.elementSequence
-}
-
-func (set *SSet) Append(keys ...string) {
+func (set *TSet) Append(keys ...t.Time) {
 	for _, key := range keys {
-		if _, ok := set.elements[key]; !ok {
-			idx := sort.Search(len(set.elementSequence), func(i int) bool {
-				return set.cmp(key, set.elementSequence[i])
-			})
-			l := len(set.elementSequence)
-			set.elementSequence = append(set.elementSequence, key)
-			for i := l; i > idx; i-- {
-				set.elements[set.elementSequence[i]] = uint32(i + 1)
-				set.elementSequence[i] = set.elementSequence[i-1]
-			}
-			set.elements[set.elementSequence[idx]] = uint32(idx + 1)
-			set.elementSequence[idx] = key
-			set.elements[key] = uint32(idx)
-		}
+		set.elements[key] = struct{}{}
 	}
 }
 
-func (set *SSet) Clear() {
-	set.elements = make(map[string]uint32)
-	set.elementSequence = set.elementSequence[:0]
+func (set *TSet) Clear() {
+	set.elements = make(map[t.Time]struct{})
 }
 
-func (set *SSet) Clone() *SSet {
-	cloned := NewSSet(set.Len(), set.cmp)
-	for idx, item := range set.elementSequence {
-		cloned.elements[item] = uint32(idx)
-		cloned.elementSequence = append(cloned.elementSequence, item)
+func (set *TSet) Clone() *TSet {
+	cloned := NewTSet(set.Len())
+	for item := range set.elements {
+		cloned.elements[item] = struct{}{}
 	}
 	return cloned
 }
 
-func (set *SSet) Difference(another *SSet) *SSet {
-	difference := NewSSet(0, set.cmp)
-	set.ForEach(func(item string) {
+func (set *TSet) Difference(another *TSet) *TSet {
+	difference := NewTSet(0)
+	set.ForEach(func(item t.Time) {
 		if !another.Contains(item) {
 			difference.Append(item)
 		}
@@ -2897,15 +2870,20 @@ func (set *SSet) Difference(another *SSet) *SSet {
 	return difference
 }
 
-func (set *SSet) Equal(another *SSet) bool {
+func (set *TSet) Equal(another *TSet) bool {
 	if set.Len() != another.Len() {
 		return false
 	}
-	return set.ContainsAll(another.elementSequence...)
+	for item := range set.elements {
+		if !another.Contains(item) {
+			return false
+		}
+	}
+	return true
 }
 
-func (set *SSet) Intersect(another *SSet) *SSet {
-	intersection := NewSSet(0, set.cmp)
+func (set *TSet) Intersect(another *TSet) *TSet {
+	intersection := NewTSet(0)
 	if set.Len() < another.Len() {
 		for item := range set.elements {
 			if another.Contains(item) {
@@ -2922,27 +2900,27 @@ func (set *SSet) Intersect(another *SSet) *SSet {
 	return intersection
 }
 
-func (set *SSet) Union(another *SSet) *SSet {
+func (set *TSet) Union(another *TSet) *TSet {
 	union := set.Clone()
 	union.InPlaceUnion(another)
 	return union
 }
 
-func (set *SSet) InPlaceUnion(another *SSet) {
-	another.ForEach(func(item string) {
+func (set *TSet) InPlaceUnion(another *TSet) {
+	another.ForEach(func(item t.Time) {
 		set.Append(item)
 	})
 }
 
-func (set *SSet) IsProperSubsetOf(another *SSet) bool {
+func (set *TSet) IsProperSubsetOf(another *TSet) bool {
 	return !set.Equal(another) && set.IsSubsetOf(another)
 }
 
-func (set *SSet) IsProperSupersetOf(another *SSet) bool {
+func (set *TSet) IsProperSupersetOf(another *TSet) bool {
 	return !set.Equal(another) && set.IsSupersetOf(another)
 }
 
-func (set *SSet) IsSubsetOf(another *SSet) bool {
+func (set *TSet) IsSubsetOf(another *TSet) bool {
 	if set.Len() > another.Len() {
 		return false
 	}
@@ -2954,31 +2932,22 @@ func (set *SSet) IsSubsetOf(another *SSet) bool {
 	return true
 }
 
-func (set *SSet) IsSupersetOf(another *SSet) bool {
+func (set *TSet) IsSupersetOf(another *TSet) bool {
 	return another.IsSubsetOf(set)
 }
 
-func (set *SSet) ForEach(f func(string)) {
+func (set *TSet) ForEach(f func(t.Time)) {
 	if set.IsEmpty() {
 		return
 	}
-	for _, item := range set.elementSequence {
+	for item := range set.elements {
 		f(item)
 	}
 }
 
-func (set *SSet) ForEachWithIndex(f func(int, string)) {
-	if set.IsEmpty() {
-		return
-	}
-	for idx, item := range set.elementSequence {
-		f(idx, item)
-	}
-}
-
-func (set *SSet) Filter(f func(string) bool) *SSet {
-	result := NewSSet(0, set.cmp)
-	set.ForEach(func(item string) {
+func (set *TSet) Filter(f func(t.Time) bool) *TSet {
+	result := NewTSet(0)
+	set.ForEach(func(item t.Time) {
 		if f(item) {
 			result.Append(item)
 		}
@@ -2986,25 +2955,16 @@ func (set *SSet) Filter(f func(string) bool) *SSet {
 	return result
 }
 
-func (set *SSet) Remove(key string) {
-	if idx, ok := set.elements[key]; ok {
-		l := set.Len()
-		delete(set.elements, key)
-		for ; idx < uint32(l-1); idx++ {
-			item := set.elementSequence[idx+1]
-			set.elementSequence[idx] = item
-			set.elements[item] = idx
-		}
-		set.elementSequence = set.elementSequence[:l-1]
-	}
+func (set *TSet) Remove(key t.Time) {
+	delete(set.elements, key)
 }
 
-func (set *SSet) Contains(key string) bool {
+func (set *TSet) Contains(key t.Time) bool {
 	_, ok := set.elements[key]
 	return ok
 }
 
-func (set *SSet) ContainsAny(keys ...string) bool {
+func (set *TSet) ContainsAny(keys ...t.Time) bool {
 	for _, key := range keys {
 		if set.Contains(key) {
 			return true
@@ -3013,7 +2973,7 @@ func (set *SSet) ContainsAny(keys ...string) bool {
 	return false
 }
 
-func (set *SSet) ContainsAll(keys ...string) bool {
+func (set *TSet) ContainsAll(keys ...t.Time) bool {
 	for _, key := range keys {
 		if !set.Contains(key) {
 			return false
@@ -3022,26 +2982,8 @@ func (set *SSet) ContainsAll(keys ...string) bool {
 	return true
 }
 
-func (set *SSet) DoUntil(f func(string) bool) int {
-	for idx, item := range set.elementSequence {
-		if f(item) {
-			return idx
-		}
-	}
-	return -1
-}
-
-func (set *SSet) DoWhile(f func(string) bool) int {
-	for idx, item := range set.elementSequence {
-		if !f(item) {
-			return idx
-		}
-	}
-	return -1
-}
-
-func (set *SSet) DoUntilError(f func(string) error) error {
-	for _, item := range set.elementSequence {
+func (set *TSet) DoUntilError(f func(t.Time) error) error {
+	for item := range set.elements {
 		if err := f(item); err != nil {
 			return err
 		}
@@ -3049,7 +2991,7 @@ func (set *SSet) DoUntilError(f func(string) error) error {
 	return nil
 }
 
-func (set *SSet) All(f func(string) bool) bool {
+func (set *TSet) All(f func(t.Time) bool) bool {
 	for item := range set.elements {
 		if !f(item) {
 			return false
@@ -3058,7 +3000,7 @@ func (set *SSet) All(f func(string) bool) bool {
 	return true
 }
 
-func (set *SSet) Any(f func(string) bool) bool {
+func (set *TSet) Any(f func(t.Time) bool) bool {
 	for item := range set.elements {
 		if f(item) {
 			return true
@@ -3067,8 +3009,8 @@ func (set *SSet) Any(f func(string) bool) bool {
 	return false
 }
 
-func (set *SSet) FindBy(f func(string) bool) *string {
-	for _, item := range set.elementSequence {
+func (set *TSet) FindBy(f func(t.Time) bool) *t.Time {
+	for item := range set.elements {
 		if f(item) {
 			return &item
 		}
@@ -3076,18 +3018,9 @@ func (set *SSet) FindBy(f func(string) bool) *string {
 	return nil
 }
 
-func (set *SSet) FindLastBy(f func(string) bool) *string {
-	for i := set.Len() - 1; i >= 0; i-- {
-		if item := set.elementSequence[i]; f(item) {
-			return &item
-		}
-	}
-	return nil
-}
-
-func (set *SSet) CountBy(f func(string) bool) int {
+func (set *TSet) CountBy(f func(t.Time) bool) int {
 	count := 0
-	set.ForEach(func(item string) {
+	set.ForEach(func(item t.Time) {
 		if f(item) {
 			count++
 		}
@@ -3095,9 +3028,9 @@ func (set *SSet) CountBy(f func(string) bool) int {
 	return count
 }
 
-func (set *SSet) GroupByBool(f func(string) bool) (trueGroup *SSet, falseGroup *SSet) {
-	trueGroup, falseGroup = NewSSet(0, set.cmp), NewSSet(0, set.cmp)
-	set.ForEach(func(item string) {
+func (set *TSet) GroupByBool(f func(t.Time) bool) (trueGroup *TSet, falseGroup *TSet) {
+	trueGroup, falseGroup = NewTSet(0), NewTSet(0)
+	set.ForEach(func(item t.Time) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -3107,13 +3040,13 @@ func (set *SSet) GroupByBool(f func(string) bool) (trueGroup *SSet, falseGroup *
 	return trueGroup, falseGroup
 }
 
-func (set *SSet) GroupByStr(f func(string) string) map[string]*SSet {
-	groups := make(map[string]*SSet)
-	set.ForEach(func(item string) {
+func (set *TSet) GroupByStr(f func(t.Time) string) map[string]*TSet {
+	groups := make(map[string]*TSet)
+	set.ForEach(func(item t.Time) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewSSet(0, set.cmp)
+			group = NewTSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -3121,13 +3054,13 @@ func (set *SSet) GroupByStr(f func(string) string) map[string]*SSet {
 	return groups
 }
 
-func (set *SSet) GroupByInt(f func(string) int) map[int]*SSet {
-	groups := make(map[int]*SSet)
-	set.ForEach(func(item string) {
+func (set *TSet) GroupByInt(f func(t.Time) int) map[int]*TSet {
+	groups := make(map[int]*TSet)
+	set.ForEach(func(item t.Time) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewSSet(0, set.cmp)
+			group = NewTSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -3135,13 +3068,13 @@ func (set *SSet) GroupByInt(f func(string) int) map[int]*SSet {
 	return groups
 }
 
-func (set *SSet) GroupBy(f func(string) interface{}) map[interface{}]*SSet {
-	groups := make(map[interface{}]*SSet)
-	set.ForEach(func(item string) {
+func (set *TSet) GroupBy(f func(t.Time) interface{}) map[interface{}]*TSet {
+	groups := make(map[interface{}]*TSet)
+	set.ForEach(func(item t.Time) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewSSet(0, set.cmp)
+			group = NewTSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -3149,10 +3082,10 @@ func (set *SSet) GroupBy(f func(string) interface{}) map[interface{}]*SSet {
 	return groups
 }
 
-// f: func(string) T
+// f: func(t.Time) T
 // return: []T
-func (set *SSet) Map(f interface{}) interface{} {
-	expected := "f should be func(string)T"
+func (set *TSet) Map(f interface{}) interface{} {
+	expected := "f should be func(t.Time)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -3161,7 +3094,7 @@ func (set *SSet) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(string)).Elem()
+	elemType := reflect.TypeOf(new(t.Time)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -3170,18 +3103,18 @@ func (set *SSet) Map(f interface{}) interface{} {
 	}
 	outType := ft.Out(0)
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item string) {
+	set.ForEach(func(item t.Time) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(string) *T
-//    func(string) (T, bool)
-//    func(string) (T, error)
+// f: func(t.Time) *T
+//    func(t.Time) (T, bool)
+//    func(t.Time) (T, error)
 // return: []T
-func (set *SSet) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(string) *T / func(string) (T, bool) / func(string) (T, error)"
+func (set *TSet) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(t.Time) *T / func(t.Time) (T, bool) / func(t.Time) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -3191,13 +3124,13 @@ func (set *SSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(string)).Elem() {
+	if in != reflect.TypeOf(new(t.Time)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(string) *T
+		// func(t.Time) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -3214,7 +3147,7 @@ func (set *SSet) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(string) (T, bool)
+			// func(t.Time) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -3222,7 +3155,7 @@ func (set *SSet) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(string) (T, error)
+			// func(t.Time) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -3237,7 +3170,7 @@ func (set *SSet) FilterMap(f interface{}) interface{} {
 	}
 
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item string) {
+	set.ForEach(func(item t.Time) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -3246,105 +3179,155 @@ func (set *SSet) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (set *SSet) Reduce(f func(string, string) string) string {
+func (set *TSet) Reduce(f func(t.Time, t.Time) t.Time) t.Time {
 	if set.IsEmpty() {
-		var defaultVal string
+		var defaultVal t.Time
 		return defaultVal
 	}
-	ret := set.elementSequence[0]
-	for _, item := range set.elementSequence[1:] {
+	var ret t.Time
+	first := true
+	for item := range set.elements {
+		if first {
+			ret = item
+			first = false
+			continue
+		}
 		ret = f(ret, item)
 	}
 	return ret
 }
 
-func (set *SSet) Fold(init string, f func(string, string) string) string {
+func (set *TSet) Fold(init t.Time, f func(t.Time, t.Time) t.Time) t.Time {
 	if set.IsEmpty() {
 		return init
 	}
-	for _, item := range set.elementSequence {
+	for item := range set.elements {
 		init = f(init, item)
 	}
 	return init
 }
 
-func (set *SSet) String() string {
-	return fmt.Sprint(set.elementSequence)
+func (set *TSet) String() string {
+	return fmt.Sprint(set.ToSlice())
 }
 
-func (set SSet) MarshalJSON() ([]byte, error) {
+func (set TSet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(set.ToSlice())
 }
 
-func (set *SSet) UnmarshalJSON(b []byte) error {
-	return fmt.Errorf("unsupported")
+func (set *TSet) UnmarshalJSON(b []byte) error {
+	s := make([]t.Time, 0)
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+	*set = *NewTSetFromSlice(s)
+	return nil
 }
 
-type TSet struct {
-	elements map[t.Time]struct{}
+type SSet struct {
+	cmp             func(i, j string) bool
+	elements        map[string]uint32
+	elementSequence []string
 }
 
-func NewTSet(capacity int) *TSet {
-	set := new(TSet)
+func NewSSet(capacity int, cmp func(i, j string) bool) *SSet {
+	set := new(SSet)
 	if capacity > 0 {
-		set.elements = make(map[t.Time]struct{}, capacity)
+		set.elements = make(map[string]uint32, capacity)
+		set.elementSequence = make([]string, 0, capacity)
 	} else {
-		set.elements = make(map[t.Time]struct{})
+		set.elements = make(map[string]uint32)
 	}
+	set.cmp = cmp
 	return set
 }
 
-func NewTSetFromSlice(items []t.Time) *TSet {
-	set := NewTSet(len(items))
+func NewSSetFromSlice(items []string, cmp func(i, j string) bool) *SSet {
+	set := NewSSet(len(items), cmp)
 	for _, item := range items {
 		set.Append(item)
 	}
 	return set
 }
 
-func (set *TSet) Len() int {
+func NewAscendingSSet(capacity int) *SSet {
+	return NewSSet(capacity, func(i, j string) bool { return i < j })
+}
+
+func NewDescendingSSet(capacity int) *SSet {
+	return NewSSet(capacity, func(i, j string) bool { return i > j })
+}
+
+func NewAscendingSSetFromSlice(items []string) *SSet {
+	return NewSSetFromSlice(items, func(i, j string) bool { return i < j })
+}
+
+func NewDescendingSSetFromSlice(items []string) *SSet {
+	return NewSSetFromSlice(items, func(i, j string) bool { return i > j })
+}
+
+func (set *SSet) Len() int {
 	if set == nil {
 		return 0
 	}
 	return len(set.elements)
 }
 
-func (set *TSet) IsEmpty() bool {
+func (set *SSet) IsEmpty() bool {
 	return set.Len() == 0
 }
 
-func (set *TSet) ToSlice() []t.Time {
+func (set *SSet) ToSlice() []string {
 	if set == nil {
 		return nil
 	}
-	s := make([]t.Time, 0, set.Len())
-	set.ForEach(func(item t.Time) {
-		s = append(s, item)
-	})
+	s := make([]string, set.Len())
+	copy(s, set.elementSequence)
 	return s
 }
 
-func (set *TSet) Append(keys ...t.Time) {
+// NOTICE: efficient but unsafe
+func (set *SSet) ToSliceRef() []string {
+	return set.elementSequence
+}
+
+func (set *SSet) Append(keys ...string) {
 	for _, key := range keys {
-		set.elements[key] = struct{}{}
+		if _, ok := set.elements[key]; !ok {
+			idx := sort.Search(len(set.elementSequence), func(i int) bool {
+				return set.cmp(key, set.elementSequence[i])
+			})
+			l := len(set.elementSequence)
+			set.elementSequence = append(set.elementSequence, key)
+			for i := l; i > idx; i-- {
+				set.elements[set.elementSequence[i]] = uint32(i + 1)
+				set.elementSequence[i] = set.elementSequence[i-1]
+			}
+			set.elements[set.elementSequence[idx]] = uint32(idx + 1)
+			set.elementSequence[idx] = key
+			set.elements[key] = uint32(idx)
+		}
 	}
 }
 
-func (set *TSet) Clear() {
-	set.elements = make(map[t.Time]struct{})
+func (set *SSet) Clear() {
+	set.elements = make(map[string]uint32)
+	set.elementSequence = set.elementSequence[:0]
 }
 
-func (set *TSet) Clone() *TSet {
-	cloned := NewTSet(set.Len())
-	for item := range set.elements {
-		cloned.elements[item] = struct{}{}
+func (set *SSet) Clone() *SSet {
+	cloned := NewSSet(set.Len(), set.cmp)
+	for idx, item := range set.elementSequence {
+		cloned.elements[item] = uint32(idx)
+		cloned.elementSequence = append(cloned.elementSequence, item)
 	}
 	return cloned
 }
 
-func (set *TSet) Difference(another *TSet) *TSet {
-	difference := NewTSet(0)
-	set.ForEach(func(item t.Time) {
+func (set *SSet) Difference(another *SSet) *SSet {
+	difference := NewSSet(0, set.cmp)
+	set.ForEach(func(item string) {
 		if !another.Contains(item) {
 			difference.Append(item)
 		}
@@ -3352,20 +3335,15 @@ func (set *TSet) Difference(another *TSet) *TSet {
 	return difference
 }
 
-func (set *TSet) Equal(another *TSet) bool {
+func (set *SSet) Equal(another *SSet) bool {
 	if set.Len() != another.Len() {
 		return false
 	}
-	for item := range set.elements {
-		if !another.Contains(item) {
-			return false
-		}
-	}
-	return true
+	return set.ContainsAll(another.elementSequence...)
 }
 
-func (set *TSet) Intersect(another *TSet) *TSet {
-	intersection := NewTSet(0)
+func (set *SSet) Intersect(another *SSet) *SSet {
+	intersection := NewSSet(0, set.cmp)
 	if set.Len() < another.Len() {
 		for item := range set.elements {
 			if another.Contains(item) {
@@ -3382,27 +3360,27 @@ func (set *TSet) Intersect(another *TSet) *TSet {
 	return intersection
 }
 
-func (set *TSet) Union(another *TSet) *TSet {
+func (set *SSet) Union(another *SSet) *SSet {
 	union := set.Clone()
 	union.InPlaceUnion(another)
 	return union
 }
 
-func (set *TSet) InPlaceUnion(another *TSet) {
-	another.ForEach(func(item t.Time) {
+func (set *SSet) InPlaceUnion(another *SSet) {
+	another.ForEach(func(item string) {
 		set.Append(item)
 	})
 }
 
-func (set *TSet) IsProperSubsetOf(another *TSet) bool {
+func (set *SSet) IsProperSubsetOf(another *SSet) bool {
 	return !set.Equal(another) && set.IsSubsetOf(another)
 }
 
-func (set *TSet) IsProperSupersetOf(another *TSet) bool {
+func (set *SSet) IsProperSupersetOf(another *SSet) bool {
 	return !set.Equal(another) && set.IsSupersetOf(another)
 }
 
-func (set *TSet) IsSubsetOf(another *TSet) bool {
+func (set *SSet) IsSubsetOf(another *SSet) bool {
 	if set.Len() > another.Len() {
 		return false
 	}
@@ -3414,22 +3392,31 @@ func (set *TSet) IsSubsetOf(another *TSet) bool {
 	return true
 }
 
-func (set *TSet) IsSupersetOf(another *TSet) bool {
+func (set *SSet) IsSupersetOf(another *SSet) bool {
 	return another.IsSubsetOf(set)
 }
 
-func (set *TSet) ForEach(f func(t.Time)) {
+func (set *SSet) ForEach(f func(string)) {
 	if set.IsEmpty() {
 		return
 	}
-	for item := range set.elements {
+	for _, item := range set.elementSequence {
 		f(item)
 	}
 }
 
-func (set *TSet) Filter(f func(t.Time) bool) *TSet {
-	result := NewTSet(0)
-	set.ForEach(func(item t.Time) {
+func (set *SSet) ForEachWithIndex(f func(int, string)) {
+	if set.IsEmpty() {
+		return
+	}
+	for idx, item := range set.elementSequence {
+		f(idx, item)
+	}
+}
+
+func (set *SSet) Filter(f func(string) bool) *SSet {
+	result := NewSSet(0, set.cmp)
+	set.ForEach(func(item string) {
 		if f(item) {
 			result.Append(item)
 		}
@@ -3437,16 +3424,25 @@ func (set *TSet) Filter(f func(t.Time) bool) *TSet {
 	return result
 }
 
-func (set *TSet) Remove(key t.Time) {
-	delete(set.elements, key)
+func (set *SSet) Remove(key string) {
+	if idx, ok := set.elements[key]; ok {
+		l := set.Len()
+		delete(set.elements, key)
+		for ; idx < uint32(l-1); idx++ {
+			item := set.elementSequence[idx+1]
+			set.elementSequence[idx] = item
+			set.elements[item] = idx
+		}
+		set.elementSequence = set.elementSequence[:l-1]
+	}
 }
 
-func (set *TSet) Contains(key t.Time) bool {
+func (set *SSet) Contains(key string) bool {
 	_, ok := set.elements[key]
 	return ok
 }
 
-func (set *TSet) ContainsAny(keys ...t.Time) bool {
+func (set *SSet) ContainsAny(keys ...string) bool {
 	for _, key := range keys {
 		if set.Contains(key) {
 			return true
@@ -3455,7 +3451,7 @@ func (set *TSet) ContainsAny(keys ...t.Time) bool {
 	return false
 }
 
-func (set *TSet) ContainsAll(keys ...t.Time) bool {
+func (set *SSet) ContainsAll(keys ...string) bool {
 	for _, key := range keys {
 		if !set.Contains(key) {
 			return false
@@ -3464,8 +3460,26 @@ func (set *TSet) ContainsAll(keys ...t.Time) bool {
 	return true
 }
 
-func (set *TSet) DoUntilError(f func(t.Time) error) error {
-	for item := range set.elements {
+func (set *SSet) DoUntil(f func(string) bool) int {
+	for idx, item := range set.elementSequence {
+		if f(item) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (set *SSet) DoWhile(f func(string) bool) int {
+	for idx, item := range set.elementSequence {
+		if !f(item) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (set *SSet) DoUntilError(f func(string) error) error {
+	for _, item := range set.elementSequence {
 		if err := f(item); err != nil {
 			return err
 		}
@@ -3473,7 +3487,7 @@ func (set *TSet) DoUntilError(f func(t.Time) error) error {
 	return nil
 }
 
-func (set *TSet) All(f func(t.Time) bool) bool {
+func (set *SSet) All(f func(string) bool) bool {
 	for item := range set.elements {
 		if !f(item) {
 			return false
@@ -3482,7 +3496,7 @@ func (set *TSet) All(f func(t.Time) bool) bool {
 	return true
 }
 
-func (set *TSet) Any(f func(t.Time) bool) bool {
+func (set *SSet) Any(f func(string) bool) bool {
 	for item := range set.elements {
 		if f(item) {
 			return true
@@ -3491,8 +3505,8 @@ func (set *TSet) Any(f func(t.Time) bool) bool {
 	return false
 }
 
-func (set *TSet) FindBy(f func(t.Time) bool) *t.Time {
-	for item := range set.elements {
+func (set *SSet) FindBy(f func(string) bool) *string {
+	for _, item := range set.elementSequence {
 		if f(item) {
 			return &item
 		}
@@ -3500,9 +3514,18 @@ func (set *TSet) FindBy(f func(t.Time) bool) *t.Time {
 	return nil
 }
 
-func (set *TSet) CountBy(f func(t.Time) bool) int {
+func (set *SSet) FindLastBy(f func(string) bool) *string {
+	for i := set.Len() - 1; i >= 0; i-- {
+		if item := set.elementSequence[i]; f(item) {
+			return &item
+		}
+	}
+	return nil
+}
+
+func (set *SSet) CountBy(f func(string) bool) int {
 	count := 0
-	set.ForEach(func(item t.Time) {
+	set.ForEach(func(item string) {
 		if f(item) {
 			count++
 		}
@@ -3510,9 +3533,9 @@ func (set *TSet) CountBy(f func(t.Time) bool) int {
 	return count
 }
 
-func (set *TSet) GroupByBool(f func(t.Time) bool) (trueGroup *TSet, falseGroup *TSet) {
-	trueGroup, falseGroup = NewTSet(0), NewTSet(0)
-	set.ForEach(func(item t.Time) {
+func (set *SSet) GroupByBool(f func(string) bool) (trueGroup *SSet, falseGroup *SSet) {
+	trueGroup, falseGroup = NewSSet(0, set.cmp), NewSSet(0, set.cmp)
+	set.ForEach(func(item string) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -3522,13 +3545,13 @@ func (set *TSet) GroupByBool(f func(t.Time) bool) (trueGroup *TSet, falseGroup *
 	return trueGroup, falseGroup
 }
 
-func (set *TSet) GroupByStr(f func(t.Time) string) map[string]*TSet {
-	groups := make(map[string]*TSet)
-	set.ForEach(func(item t.Time) {
+func (set *SSet) GroupByStr(f func(string) string) map[string]*SSet {
+	groups := make(map[string]*SSet)
+	set.ForEach(func(item string) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewTSet(0)
+			group = NewSSet(0, set.cmp)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -3536,13 +3559,13 @@ func (set *TSet) GroupByStr(f func(t.Time) string) map[string]*TSet {
 	return groups
 }
 
-func (set *TSet) GroupByInt(f func(t.Time) int) map[int]*TSet {
-	groups := make(map[int]*TSet)
-	set.ForEach(func(item t.Time) {
+func (set *SSet) GroupByInt(f func(string) int) map[int]*SSet {
+	groups := make(map[int]*SSet)
+	set.ForEach(func(item string) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewTSet(0)
+			group = NewSSet(0, set.cmp)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -3550,13 +3573,13 @@ func (set *TSet) GroupByInt(f func(t.Time) int) map[int]*TSet {
 	return groups
 }
 
-func (set *TSet) GroupBy(f func(t.Time) interface{}) map[interface{}]*TSet {
-	groups := make(map[interface{}]*TSet)
-	set.ForEach(func(item t.Time) {
+func (set *SSet) GroupBy(f func(string) interface{}) map[interface{}]*SSet {
+	groups := make(map[interface{}]*SSet)
+	set.ForEach(func(item string) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewTSet(0)
+			group = NewSSet(0, set.cmp)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -3564,10 +3587,10 @@ func (set *TSet) GroupBy(f func(t.Time) interface{}) map[interface{}]*TSet {
 	return groups
 }
 
-// f: func(t.Time) T
+// f: func(string) T
 // return: []T
-func (set *TSet) Map(f interface{}) interface{} {
-	expected := "f should be func(t.Time)T"
+func (set *SSet) Map(f interface{}) interface{} {
+	expected := "f should be func(string)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -3576,7 +3599,7 @@ func (set *TSet) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(t.Time)).Elem()
+	elemType := reflect.TypeOf(new(string)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -3585,18 +3608,18 @@ func (set *TSet) Map(f interface{}) interface{} {
 	}
 	outType := ft.Out(0)
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item t.Time) {
+	set.ForEach(func(item string) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(t.Time) *T
-//    func(t.Time) (T, bool)
-//    func(t.Time) (T, error)
+// f: func(string) *T
+//    func(string) (T, bool)
+//    func(string) (T, error)
 // return: []T
-func (set *TSet) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(t.Time) *T / func(t.Time) (T, bool) / func(t.Time) (T, error)"
+func (set *SSet) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(string) *T / func(string) (T, bool) / func(string) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -3606,13 +3629,13 @@ func (set *TSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(t.Time)).Elem() {
+	if in != reflect.TypeOf(new(string)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(t.Time) *T
+		// func(string) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -3629,7 +3652,7 @@ func (set *TSet) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(t.Time) (T, bool)
+			// func(string) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -3637,7 +3660,7 @@ func (set *TSet) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(t.Time) (T, error)
+			// func(string) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -3652,7 +3675,7 @@ func (set *TSet) FilterMap(f interface{}) interface{} {
 	}
 
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item t.Time) {
+	set.ForEach(func(item string) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -3661,213 +3684,105 @@ func (set *TSet) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (set *TSet) Reduce(f func(t.Time, t.Time) t.Time) t.Time {
+func (set *SSet) Reduce(f func(string, string) string) string {
 	if set.IsEmpty() {
-		var defaultVal t.Time
+		var defaultVal string
 		return defaultVal
 	}
-	var ret t.Time
-	first := true
-	for item := range set.elements {
-		if first {
-			ret = item
-			first = false
-			continue
-		}
+	ret := set.elementSequence[0]
+	for _, item := range set.elementSequence[1:] {
 		ret = f(ret, item)
 	}
 	return ret
 }
 
-func (set *TSet) Fold(init t.Time, f func(t.Time, t.Time) t.Time) t.Time {
+func (set *SSet) Fold(init string, f func(string, string) string) string {
 	if set.IsEmpty() {
 		return init
 	}
-	for item := range set.elements {
+	for _, item := range set.elementSequence {
 		init = f(init, item)
 	}
 	return init
 }
 
-func (set *TSet) String() string {
-	return fmt.Sprint(set.ToSlice())
+func (set *SSet) String() string {
+	return fmt.Sprint(set.elementSequence)
 }
 
-func (set TSet) MarshalJSON() ([]byte, error) {
+func (set SSet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(set.ToSlice())
 }
 
-func (set *TSet) UnmarshalJSON(b []byte) error {
-	s := make([]t.Time, 0)
-	err := json.Unmarshal(b, &s)
-	if err != nil {
-		return err
-	}
-	*set = *NewTSetFromSlice(s)
-	return nil
+func (set *SSet) UnmarshalJSON(b []byte) error {
+	return fmt.Errorf("unsupported")
 }
 
-func (b *b) getC() *c {
-	if b == nil {
-		var defaultVal *c
-		return defaultVal
+type PSet struct {
+	elements map[plugin.Plugin]struct{}
+}
+
+func NewPSet(capacity int) *PSet {
+	set := new(PSet)
+	if capacity > 0 {
+		set.elements = make(map[plugin.Plugin]struct{}, capacity)
+	} else {
+		set.elements = make(map[plugin.Plugin]struct{})
 	}
-	return b.c
+	return set
 }
 
-func (b *b) GetC() *c {
-	if b == nil {
-		var defaultVal *c
-		return defaultVal
+func NewPSetFromSlice(items []plugin.Plugin) *PSet {
+	set := NewPSet(len(items))
+	for _, item := range items {
+		set.Append(item)
 	}
-	return b.C
+	return set
 }
 
-func (c *c) getAbc() string {
-	if c == nil {
-		var defaultVal string
-		return defaultVal
+func (set *PSet) Len() int {
+	if set == nil {
+		return 0
 	}
-	return c.abc
+	return len(set.elements)
 }
 
-func (c *c) GetDef() *int {
-	if c == nil {
-		var defaultVal *int
-		return defaultVal
-	}
-	return c.Def
-}
-
-func (c *c) TakeTime() t.Time {
-	if c == nil {
-		var defaultVal t.Time
-		return defaultVal
-	}
-	return c.a
-}
-
-func (c *c) getB() []string {
-	if c == nil {
-		var defaultVal []string
-		return defaultVal
-	}
-	return c.b
-}
-
-func (c *c) getBb() [3]string {
-	if c == nil {
-		var defaultVal [3]string
-		return defaultVal
-	}
-	return c.bb
-}
-
-func (c *c) getC() map[int]string {
-	if c == nil {
-		var defaultVal map[int]string
-		return defaultVal
-	}
-	return c.c
-}
-
-func (c *c) getD() chan int {
-	if c == nil {
-		var defaultVal chan int
-		return defaultVal
-	}
-	return c.d
-}
-
-func (c *c) getE() chan<- int {
-	if c == nil {
-		var defaultVal chan<- int
-		return defaultVal
-	}
-	return c.e
-}
-
-func (c *c) getF() <-chan int {
-	if c == nil {
-		var defaultVal <-chan int
-		return defaultVal
-	}
-	return c.f
-}
-
-func (c *c) getFf() func(int, string, c, d t.Time) (a, b bool, e error) {
-	if c == nil {
-		var defaultVal func(int, string, c, d t.Time) (a, b bool, e error)
-		return defaultVal
-	}
-	return c.ff
-}
-
-type hSet struct {
-	elements map[http.Handler]struct{}
-}
-
-func newHSet(capacity int) *hSet {
-	set := new(hSet)
-	if capacity > 0 {
-		set.elements = make(map[http.Handler]struct{}, capacity)
-	} else {
-		set.elements = make(map[http.Handler]struct{})
-	}
-	return set
-}
-
-func newHSetFromSlice(items []http.Handler) *hSet {
-	set := newHSet(len(items))
-	for _, item := range items {
-		set.Append(item)
-	}
-	return set
-}
-
-func (set *hSet) Len() int {
-	if set == nil {
-		return 0
-	}
-	return len(set.elements)
-}
-
-func (set *hSet) IsEmpty() bool {
+func (set *PSet) IsEmpty() bool {
 	return set.Len() == 0
 }
 
-func (set *hSet) ToSlice() []http.Handler {
+func (set *PSet) ToSlice() []plugin.Plugin {
 	if set == nil {
 		return nil
 	}
-	s := make([]http.Handler, 0, set.Len())
-	set.ForEach(func(item http.Handler) {
+	s := make([]plugin.Plugin, 0, set.Len())
+	set.ForEach(func(item plugin.Plugin) {
 		s = append(s, item)
 	})
 	return s
 }
 
-func (set *hSet) Append(keys ...http.Handler) {
+func (set *PSet) Append(keys ...plugin.Plugin) {
 	for _, key := range keys {
 		set.elements[key] = struct{}{}
 	}
 }
 
-func (set *hSet) Clear() {
-	set.elements = make(map[http.Handler]struct{})
+func (set *PSet) Clear() {
+	set.elements = make(map[plugin.Plugin]struct{})
 }
 
-func (set *hSet) Clone() *hSet {
-	cloned := newHSet(set.Len())
+func (set *PSet) Clone() *PSet {
+	cloned := NewPSet(set.Len())
 	for item := range set.elements {
 		cloned.elements[item] = struct{}{}
 	}
 	return cloned
 }
 
-func (set *hSet) Difference(another *hSet) *hSet {
-	difference := newHSet(0)
-	set.ForEach(func(item http.Handler) {
+func (set *PSet) Difference(another *PSet) *PSet {
+	difference := NewPSet(0)
+	set.ForEach(func(item plugin.Plugin) {
 		if !another.Contains(item) {
 			difference.Append(item)
 		}
@@ -3875,7 +3790,7 @@ func (set *hSet) Difference(another *hSet) *hSet {
 	return difference
 }
 
-func (set *hSet) Equal(another *hSet) bool {
+func (set *PSet) Equal(another *PSet) bool {
 	if set.Len() != another.Len() {
 		return false
 	}
@@ -3887,8 +3802,8 @@ func (set *hSet) Equal(another *hSet) bool {
 	return true
 }
 
-func (set *hSet) Intersect(another *hSet) *hSet {
-	intersection := newHSet(0)
+func (set *PSet) Intersect(another *PSet) *PSet {
+	intersection := NewPSet(0)
 	if set.Len() < another.Len() {
 		for item := range set.elements {
 			if another.Contains(item) {
@@ -3905,27 +3820,27 @@ func (set *hSet) Intersect(another *hSet) *hSet {
 	return intersection
 }
 
-func (set *hSet) Union(another *hSet) *hSet {
+func (set *PSet) Union(another *PSet) *PSet {
 	union := set.Clone()
 	union.InPlaceUnion(another)
 	return union
 }
 
-func (set *hSet) InPlaceUnion(another *hSet) {
-	another.ForEach(func(item http.Handler) {
+func (set *PSet) InPlaceUnion(another *PSet) {
+	another.ForEach(func(item plugin.Plugin) {
 		set.Append(item)
 	})
 }
 
-func (set *hSet) IsProperSubsetOf(another *hSet) bool {
+func (set *PSet) IsProperSubsetOf(another *PSet) bool {
 	return !set.Equal(another) && set.IsSubsetOf(another)
 }
 
-func (set *hSet) IsProperSupersetOf(another *hSet) bool {
+func (set *PSet) IsProperSupersetOf(another *PSet) bool {
 	return !set.Equal(another) && set.IsSupersetOf(another)
 }
 
-func (set *hSet) IsSubsetOf(another *hSet) bool {
+func (set *PSet) IsSubsetOf(another *PSet) bool {
 	if set.Len() > another.Len() {
 		return false
 	}
@@ -3937,11 +3852,11 @@ func (set *hSet) IsSubsetOf(another *hSet) bool {
 	return true
 }
 
-func (set *hSet) IsSupersetOf(another *hSet) bool {
+func (set *PSet) IsSupersetOf(another *PSet) bool {
 	return another.IsSubsetOf(set)
 }
 
-func (set *hSet) ForEach(f func(http.Handler)) {
+func (set *PSet) ForEach(f func(plugin.Plugin)) {
 	if set.IsEmpty() {
 		return
 	}
@@ -3950,9 +3865,9 @@ func (set *hSet) ForEach(f func(http.Handler)) {
 	}
 }
 
-func (set *hSet) Filter(f func(http.Handler) bool) *hSet {
-	result := newHSet(0)
-	set.ForEach(func(item http.Handler) {
+func (set *PSet) Filter(f func(plugin.Plugin) bool) *PSet {
+	result := NewPSet(0)
+	set.ForEach(func(item plugin.Plugin) {
 		if f(item) {
 			result.Append(item)
 		}
@@ -3960,16 +3875,16 @@ func (set *hSet) Filter(f func(http.Handler) bool) *hSet {
 	return result
 }
 
-func (set *hSet) Remove(key http.Handler) {
+func (set *PSet) Remove(key plugin.Plugin) {
 	delete(set.elements, key)
 }
 
-func (set *hSet) Contains(key http.Handler) bool {
+func (set *PSet) Contains(key plugin.Plugin) bool {
 	_, ok := set.elements[key]
 	return ok
 }
 
-func (set *hSet) ContainsAny(keys ...http.Handler) bool {
+func (set *PSet) ContainsAny(keys ...plugin.Plugin) bool {
 	for _, key := range keys {
 		if set.Contains(key) {
 			return true
@@ -3978,7 +3893,7 @@ func (set *hSet) ContainsAny(keys ...http.Handler) bool {
 	return false
 }
 
-func (set *hSet) ContainsAll(keys ...http.Handler) bool {
+func (set *PSet) ContainsAll(keys ...plugin.Plugin) bool {
 	for _, key := range keys {
 		if !set.Contains(key) {
 			return false
@@ -3987,7 +3902,7 @@ func (set *hSet) ContainsAll(keys ...http.Handler) bool {
 	return true
 }
 
-func (set *hSet) DoUntilError(f func(http.Handler) error) error {
+func (set *PSet) DoUntilError(f func(plugin.Plugin) error) error {
 	for item := range set.elements {
 		if err := f(item); err != nil {
 			return err
@@ -3996,7 +3911,7 @@ func (set *hSet) DoUntilError(f func(http.Handler) error) error {
 	return nil
 }
 
-func (set *hSet) All(f func(http.Handler) bool) bool {
+func (set *PSet) All(f func(plugin.Plugin) bool) bool {
 	for item := range set.elements {
 		if !f(item) {
 			return false
@@ -4005,7 +3920,7 @@ func (set *hSet) All(f func(http.Handler) bool) bool {
 	return true
 }
 
-func (set *hSet) Any(f func(http.Handler) bool) bool {
+func (set *PSet) Any(f func(plugin.Plugin) bool) bool {
 	for item := range set.elements {
 		if f(item) {
 			return true
@@ -4014,7 +3929,7 @@ func (set *hSet) Any(f func(http.Handler) bool) bool {
 	return false
 }
 
-func (set *hSet) FindBy(f func(http.Handler) bool) *http.Handler {
+func (set *PSet) FindBy(f func(plugin.Plugin) bool) *plugin.Plugin {
 	for item := range set.elements {
 		if f(item) {
 			return &item
@@ -4023,9 +3938,9 @@ func (set *hSet) FindBy(f func(http.Handler) bool) *http.Handler {
 	return nil
 }
 
-func (set *hSet) CountBy(f func(http.Handler) bool) int {
+func (set *PSet) CountBy(f func(plugin.Plugin) bool) int {
 	count := 0
-	set.ForEach(func(item http.Handler) {
+	set.ForEach(func(item plugin.Plugin) {
 		if f(item) {
 			count++
 		}
@@ -4033,9 +3948,9 @@ func (set *hSet) CountBy(f func(http.Handler) bool) int {
 	return count
 }
 
-func (set *hSet) GroupByBool(f func(http.Handler) bool) (trueGroup *hSet, falseGroup *hSet) {
-	trueGroup, falseGroup = newHSet(0), newHSet(0)
-	set.ForEach(func(item http.Handler) {
+func (set *PSet) GroupByBool(f func(plugin.Plugin) bool) (trueGroup *PSet, falseGroup *PSet) {
+	trueGroup, falseGroup = NewPSet(0), NewPSet(0)
+	set.ForEach(func(item plugin.Plugin) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -4045,13 +3960,13 @@ func (set *hSet) GroupByBool(f func(http.Handler) bool) (trueGroup *hSet, falseG
 	return trueGroup, falseGroup
 }
 
-func (set *hSet) GroupByStr(f func(http.Handler) string) map[string]*hSet {
-	groups := make(map[string]*hSet)
-	set.ForEach(func(item http.Handler) {
+func (set *PSet) GroupByStr(f func(plugin.Plugin) string) map[string]*PSet {
+	groups := make(map[string]*PSet)
+	set.ForEach(func(item plugin.Plugin) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newHSet(0)
+			group = NewPSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4059,13 +3974,13 @@ func (set *hSet) GroupByStr(f func(http.Handler) string) map[string]*hSet {
 	return groups
 }
 
-func (set *hSet) GroupByInt(f func(http.Handler) int) map[int]*hSet {
-	groups := make(map[int]*hSet)
-	set.ForEach(func(item http.Handler) {
+func (set *PSet) GroupByInt(f func(plugin.Plugin) int) map[int]*PSet {
+	groups := make(map[int]*PSet)
+	set.ForEach(func(item plugin.Plugin) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newHSet(0)
+			group = NewPSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4073,13 +3988,13 @@ func (set *hSet) GroupByInt(f func(http.Handler) int) map[int]*hSet {
 	return groups
 }
 
-func (set *hSet) GroupBy(f func(http.Handler) interface{}) map[interface{}]*hSet {
-	groups := make(map[interface{}]*hSet)
-	set.ForEach(func(item http.Handler) {
+func (set *PSet) GroupBy(f func(plugin.Plugin) interface{}) map[interface{}]*PSet {
+	groups := make(map[interface{}]*PSet)
+	set.ForEach(func(item plugin.Plugin) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newHSet(0)
+			group = NewPSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4087,10 +4002,10 @@ func (set *hSet) GroupBy(f func(http.Handler) interface{}) map[interface{}]*hSet
 	return groups
 }
 
-// f: func(http.Handler) T
+// f: func(plugin.Plugin) T
 // return: []T
-func (set *hSet) Map(f interface{}) interface{} {
-	expected := "f should be func(http.Handler)T"
+func (set *PSet) Map(f interface{}) interface{} {
+	expected := "f should be func(plugin.Plugin)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -4099,7 +4014,7 @@ func (set *hSet) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(http.Handler)).Elem()
+	elemType := reflect.TypeOf(new(plugin.Plugin)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -4108,18 +4023,18 @@ func (set *hSet) Map(f interface{}) interface{} {
 	}
 	outType := ft.Out(0)
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item http.Handler) {
+	set.ForEach(func(item plugin.Plugin) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(http.Handler) *T
-//    func(http.Handler) (T, bool)
-//    func(http.Handler) (T, error)
+// f: func(plugin.Plugin) *T
+//    func(plugin.Plugin) (T, bool)
+//    func(plugin.Plugin) (T, error)
 // return: []T
-func (set *hSet) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(http.Handler) *T / func(http.Handler) (T, bool) / func(http.Handler) (T, error)"
+func (set *PSet) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(plugin.Plugin) *T / func(plugin.Plugin) (T, bool) / func(plugin.Plugin) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -4129,13 +4044,13 @@ func (set *hSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(http.Handler)).Elem() {
+	if in != reflect.TypeOf(new(plugin.Plugin)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(http.Handler) *T
+		// func(plugin.Plugin) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -4152,7 +4067,7 @@ func (set *hSet) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(http.Handler) (T, bool)
+			// func(plugin.Plugin) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -4160,7 +4075,7 @@ func (set *hSet) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(http.Handler) (T, error)
+			// func(plugin.Plugin) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -4175,7 +4090,7 @@ func (set *hSet) FilterMap(f interface{}) interface{} {
 	}
 
 	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item http.Handler) {
+	set.ForEach(func(item plugin.Plugin) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -4184,12 +4099,12 @@ func (set *hSet) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (set *hSet) Reduce(f func(http.Handler, http.Handler) http.Handler) http.Handler {
+func (set *PSet) Reduce(f func(plugin.Plugin, plugin.Plugin) plugin.Plugin) plugin.Plugin {
 	if set.IsEmpty() {
-		var defaultVal http.Handler
+		var defaultVal plugin.Plugin
 		return defaultVal
 	}
-	var ret http.Handler
+	var ret plugin.Plugin
 	first := true
 	for item := range set.elements {
 		if first {
@@ -4202,7 +4117,7 @@ func (set *hSet) Reduce(f func(http.Handler, http.Handler) http.Handler) http.Ha
 	return ret
 }
 
-func (set *hSet) Fold(init http.Handler, f func(http.Handler, http.Handler) http.Handler) http.Handler {
+func (set *PSet) Fold(init plugin.Plugin, f func(plugin.Plugin, plugin.Plugin) plugin.Plugin) plugin.Plugin {
 	if set.IsEmpty() {
 		return init
 	}
@@ -4212,270 +4127,247 @@ func (set *hSet) Fold(init http.Handler, f func(http.Handler, http.Handler) http
 	return init
 }
 
-func (set *hSet) String() string {
+func (set *PSet) String() string {
 	return fmt.Sprint(set.ToSlice())
 }
 
-func (set hSet) MarshalJSON() ([]byte, error) {
+func (set PSet) MarshalJSON() ([]byte, error) {
 	return json.Marshal(set.ToSlice())
 }
 
-func (set *hSet) UnmarshalJSON(b []byte) error {
-	s := make([]http.Handler, 0)
+func (set *PSet) UnmarshalJSON(b []byte) error {
+	s := make([]plugin.Plugin, 0)
 	err := json.Unmarshal(b, &s)
 	if err != nil {
 		return err
 	}
-	*set = *newHSetFromSlice(s)
+	*set = *NewPSetFromSlice(s)
 	return nil
 }
 
-type hSlice struct {
-	elements []http.Handler
+type myTypeSet struct {
+	elements map[MyType]struct{}
 }
 
-func newHSlice(capacity int) *hSlice {
-	return &hSlice{
-		elements: make([]http.Handler, 0, capacity),
+func newMyTypeSet(capacity int) *myTypeSet {
+	set := new(myTypeSet)
+	if capacity > 0 {
+		set.elements = make(map[MyType]struct{}, capacity)
+	} else {
+		set.elements = make(map[MyType]struct{})
 	}
+	return set
 }
 
-func newHSliceFromSlice(slice []http.Handler) *hSlice {
-	return &hSlice{
-		elements: slice,
+func newMyTypeSetFromSlice(items []MyType) *myTypeSet {
+	set := newMyTypeSet(len(items))
+	for _, item := range items {
+		set.Append(item)
 	}
+	return set
 }
 
-func (s *hSlice) Len() int {
-	if s == nil {
+func (set *myTypeSet) Len() int {
+	if set == nil {
 		return 0
 	}
-	return len(s.elements)
+	return len(set.elements)
 }
 
-func (s *hSlice) IsEmpty() bool {
-	return s.Len() == 0
+func (set *myTypeSet) IsEmpty() bool {
+	return set.Len() == 0
 }
 
-func (s *hSlice) Append(items ...http.Handler) {
-	s.elements = append(s.elements, items...)
+func (set *myTypeSet) ToSlice() []MyType {
+	if set == nil {
+		return nil
+	}
+	s := make([]MyType, 0, set.Len())
+	set.ForEach(func(item MyType) {
+		s = append(s, item)
+	})
+	return s
 }
 
-func (s *hSlice) Clone() *hSlice {
-	cloned := &hSlice{
-		elements: make([]http.Handler, s.Len()),
+func (set *myTypeSet) Append(keys ...MyType) {
+	for _, key := range keys {
+		set.elements[key] = struct{}{}
 	}
-	copy(cloned.elements, s.elements)
-	return cloned
 }
 
-func (s *hSlice) ToSlice() []http.Handler {
-	slice := make([]http.Handler, s.Len())
-	copy(slice, s.elements)
-	return slice
+func (set *myTypeSet) Clear() {
+	set.elements = make(map[MyType]struct{})
 }
 
-func (s *hSlice) ToSliceRef() []http.Handler {
-	return s.elements
+func (set *myTypeSet) Clone() *myTypeSet {
+	cloned := newMyTypeSet(set.Len())
+	for item := range set.elements {
+		cloned.elements[item] = struct{}{}
+	}
+	return cloned
 }
 
-func (s *hSlice) Clear() {
-	s.elements = s.elements[:0]
+func (set *myTypeSet) Difference(another *myTypeSet) *myTypeSet {
+	difference := newMyTypeSet(0)
+	set.ForEach(func(item MyType) {
+		if !another.Contains(item) {
+			difference.Append(item)
+		}
+	})
+	return difference
 }
 
-func (s *hSlice) Equal(another *hSlice) bool {
-	if s.Len() != another.Len() {
+func (set *myTypeSet) Equal(another *myTypeSet) bool {
+	if set.Len() != another.Len() {
 		return false
 	}
-	for idx, item := range s.elements {
-		if item != another.elements[idx] {
+	for item := range set.elements {
+		if !another.Contains(item) {
 			return false
 		}
 	}
-	return false
+	return true
 }
 
-func (s *hSlice) Insert(idx int, items ...http.Handler) {
-	if idx < 0 {
-		idx += s.Len()
-	}
-	if l := len(s.elements) + len(items); l > cap(s.elements) {
-		// reallocate
-		result := make([]http.Handler, l)
-		copy(result, s.elements[:idx])
-		copy(result[idx:], items)
-		copy(result[idx+len(items):], s.elements[idx:])
-		s.elements = result
-		return
+func (set *myTypeSet) Intersect(another *myTypeSet) *myTypeSet {
+	intersection := newMyTypeSet(0)
+	if set.Len() < another.Len() {
+		for item := range set.elements {
+			if another.Contains(item) {
+				intersection.Append(item)
+			}
+		}
+	} else {
+		for item := range another.elements {
+			if set.Contains(item) {
+				intersection.Append(item)
+			}
+		}
 	}
-
-	l := s.Len()
-	s.elements = append(s.elements, items...)
-	copy(s.elements[idx+len(items):], s.elements[idx:l])
-	copy(s.elements[idx:], items)
+	return intersection
 }
 
-func (s *hSlice) Remove(idx int) {
-	if idx < 0 {
-		idx += s.Len()
-	}
-	s.elements = append(s.elements[:idx], s.elements[idx+1:]...)
+func (set *myTypeSet) Union(another *myTypeSet) *myTypeSet {
+	union := set.Clone()
+	union.InPlaceUnion(another)
+	return union
 }
 
-func (s *hSlice) RemoveRange(from, to int) {
-	if from < 0 {
-		from += s.Len()
-	}
-	if to < 0 {
-		to += s.Len()
-	}
-	s.elements = append(s.elements[:from], s.elements[to+1:]...)
+func (set *myTypeSet) InPlaceUnion(another *myTypeSet) {
+	another.ForEach(func(item MyType) {
+		set.Append(item)
+	})
 }
 
-func (s *hSlice) RemoveFrom(idx int) {
-	if idx < 0 {
-		idx += s.Len()
-	}
-	s.elements = s.elements[:idx]
+func (set *myTypeSet) IsProperSubsetOf(another *myTypeSet) bool {
+	return !set.Equal(another) && set.IsSubsetOf(another)
 }
 
-func (s *hSlice) RemoveTo(idx int) {
-	if idx < 0 {
-		idx += s.Len()
-	}
-	s.elements = s.elements[idx+1:]
+func (set *myTypeSet) IsProperSupersetOf(another *myTypeSet) bool {
+	return !set.Equal(another) && set.IsSupersetOf(another)
 }
 
-func (s *hSlice) Concat(another *hSlice) *hSlice {
-	result := s.Clone()
-	if another.IsEmpty() {
-		return result
+func (set *myTypeSet) IsSubsetOf(another *myTypeSet) bool {
+	if set.Len() > another.Len() {
+		return false
 	}
-	result.Append(another.elements...)
-	return result
-}
-
-func (s *hSlice) InPlaceConcat(another *hSlice) {
-	if another.IsEmpty() {
-		return
+	for item := range set.elements {
+		if !another.Contains(item) {
+			return false
+		}
 	}
-	s.Append(another.elements...)
+	return true
 }
 
-func (s *hSlice) ForEach(f func(http.Handler)) {
-	if s.IsEmpty() {
-		return
-	}
-	for _, item := range s.elements {
-		f(item)
-	}
+func (set *myTypeSet) IsSupersetOf(another *myTypeSet) bool {
+	return another.IsSubsetOf(set)
 }
 
-func (s *hSlice) ForEachWithIndex(f func(int, http.Handler)) {
-	if s.IsEmpty() {
+func (set *myTypeSet) ForEach(f func(MyType)) {
+	if set.IsEmpty() {
 		return
 	}
-	for idx, item := range s.elements {
-		f(idx, item)
+	for item := range set.elements {
+		f(item)
 	}
 }
 
-func (s *hSlice) Filter(f func(http.Handler) bool) *hSlice {
-	result := newHSlice(0)
-	for _, item := range s.elements {
+func (set *myTypeSet) Filter(f func(MyType) bool) *myTypeSet {
+	result := newMyTypeSet(0)
+	set.ForEach(func(item MyType) {
 		if f(item) {
 			result.Append(item)
 		}
-	}
+	})
 	return result
 }
 
-func (s *hSlice) Index(idx int) *http.Handler {
-	if idx < 0 {
-		idx += s.Len()
-	}
-	return &s.elements[idx]
+func (set *myTypeSet) Remove(key MyType) {
+	delete(set.elements, key)
 }
 
-func (s *hSlice) IndexRange(from, to int) *hSlice {
-	if from < 0 {
-		from += s.Len()
-	}
-	if to < 0 {
-		to += s.Len()
-	}
-	return newHSliceFromSlice(s.elements[from:to])
+func (set *myTypeSet) Contains(key MyType) bool {
+	_, ok := set.elements[key]
+	return ok
 }
 
-func (s *hSlice) IndexFrom(idx int) *hSlice {
-	if idx < 0 {
-		idx += s.Len()
+func (set *myTypeSet) ContainsAny(keys ...MyType) bool {
+	for _, key := range keys {
+		if set.Contains(key) {
+			return true
+		}
 	}
-	return newHSliceFromSlice(s.elements[idx:])
+	return false
 }
 
-func (s *hSlice) IndexTo(idx int) *hSlice {
-	if idx < 0 {
-		idx += s.Len()
+func (set *myTypeSet) ContainsAll(keys ...MyType) bool {
+	for _, key := range keys {
+		if !set.Contains(key) {
+			return false
+		}
 	}
-	return newHSliceFromSlice(s.elements[:idx])
+	return true
 }
 
-func (s *hSlice) Find(item http.Handler) int {
-	if s.IsEmpty() {
-		return -1
-	}
-	for idx, n := range s.elements {
-		if n == item {
-			return idx
+func (set *myTypeSet) DoUntilError(f func(MyType) error) error {
+	for item := range set.elements {
+		if err := f(item); err != nil {
+			return err
 		}
 	}
-	return -1
+	return nil
 }
 
-func (s *hSlice) FindLast(item http.Handler) int {
-	for idx := s.Len() - 1; idx >= 0; idx-- {
-		if s.elements[idx] == item {
-			return idx
+func (set *myTypeSet) All(f func(MyType) bool) bool {
+	for item := range set.elements {
+		if !f(item) {
+			return false
 		}
 	}
-	return -1
+	return true
 }
 
-func (s *hSlice) FindBy(f func(http.Handler) bool) int {
-	if s.IsEmpty() {
-		return -1
-	}
-	for idx, n := range s.elements {
-		if f(n) {
-			return idx
+func (set *myTypeSet) Any(f func(MyType) bool) bool {
+	for item := range set.elements {
+		if f(item) {
+			return true
 		}
 	}
-	return -1
+	return false
 }
 
-func (s *hSlice) FindLastBy(f func(http.Handler) bool) int {
-	for idx := s.Len() - 1; idx >= 0; idx-- {
-		if f(s.elements[idx]) {
-			return idx
+func (set *myTypeSet) FindBy(f func(MyType) bool) *MyType {
+	for item := range set.elements {
+		if f(item) {
+			return &item
 		}
 	}
-	return -1
-}
-
-func (s *hSlice) Count(item http.Handler) uint {
-	count := uint(0)
-	s.ForEach(func(n http.Handler) {
-		if n == item {
-			count++
-		}
-	})
-	return count
+	return nil
 }
 
-func (s *hSlice) CountBy(f func(http.Handler) bool) uint {
-	count := uint(0)
-	s.ForEach(func(item http.Handler) {
+func (set *myTypeSet) CountBy(f func(MyType) bool) int {
+	count := 0
+	set.ForEach(func(item MyType) {
 		if f(item) {
 			count++
 		}
@@ -4483,9 +4375,9 @@ func (s *hSlice) CountBy(f func(http.Handler) bool) uint {
 	return count
 }
 
-func (s *hSlice) GroupByBool(f func(http.Handler) bool) (trueGroup, falseGroup *hSlice) {
-	trueGroup, falseGroup = newHSlice(0), newHSlice(0)
-	s.ForEach(func(item http.Handler) {
+func (set *myTypeSet) GroupByBool(f func(MyType) bool) (trueGroup *myTypeSet, falseGroup *myTypeSet) {
+	trueGroup, falseGroup = newMyTypeSet(0), newMyTypeSet(0)
+	set.ForEach(func(item MyType) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -4495,13 +4387,13 @@ func (s *hSlice) GroupByBool(f func(http.Handler) bool) (trueGroup, falseGroup *
 	return trueGroup, falseGroup
 }
 
-func (s hSlice) GroupByStr(f func(http.Handler) string) map[string]*hSlice {
-	groups := make(map[string]*hSlice)
-	s.ForEach(func(item http.Handler) {
+func (set *myTypeSet) GroupByStr(f func(MyType) string) map[string]*myTypeSet {
+	groups := make(map[string]*myTypeSet)
+	set.ForEach(func(item MyType) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newHSlice(0)
+			group = newMyTypeSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4509,13 +4401,13 @@ func (s hSlice) GroupByStr(f func(http.Handler) string) map[string]*hSlice {
 	return groups
 }
 
-func (s hSlice) GroupByInt(f func(http.Handler) int) map[int]*hSlice {
-	groups := make(map[int]*hSlice)
-	s.ForEach(func(item http.Handler) {
+func (set *myTypeSet) GroupByInt(f func(MyType) int) map[int]*myTypeSet {
+	groups := make(map[int]*myTypeSet)
+	set.ForEach(func(item MyType) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newHSlice(0)
+			group = newMyTypeSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4523,13 +4415,13 @@ func (s hSlice) GroupByInt(f func(http.Handler) int) map[int]*hSlice {
 	return groups
 }
 
-func (s *hSlice) GroupBy(f func(http.Handler) interface{}) map[interface{}]*hSlice {
-	groups := make(map[interface{}]*hSlice)
-	s.ForEach(func(item http.Handler) {
+func (set *myTypeSet) GroupBy(f func(MyType) interface{}) map[interface{}]*myTypeSet {
+	groups := make(map[interface{}]*myTypeSet)
+	set.ForEach(func(item MyType) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = newHSlice(0)
+			group = newMyTypeSet(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4537,10 +4429,10 @@ func (s *hSlice) GroupBy(f func(http.Handler) interface{}) map[interface{}]*hSli
 	return groups
 }
 
-// f: func(http.Handler) T
+// f: func(MyType) T
 // return: []T
-func (s *hSlice) Map(f interface{}) interface{} {
-	expected := "f should be func(http.Handler)T"
+func (set *myTypeSet) Map(f interface{}) interface{} {
+	expected := "f should be func(MyType)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -4549,7 +4441,7 @@ func (s *hSlice) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(http.Handler)).Elem()
+	elemType := reflect.TypeOf(new(MyType)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -4557,19 +4449,19 @@ func (s *hSlice) Map(f interface{}) interface{} {
 		panic(expected)
 	}
 	outType := ft.Out(0)
-	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
-	s.ForEach(func(item http.Handler) {
+	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
+	set.ForEach(func(item MyType) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(http.Handler) *T
-//    func(http.Handler) (T, bool)
-//    func(http.Handler) (T, error)
+// f: func(MyType) *T
+//    func(MyType) (T, bool)
+//    func(MyType) (T, error)
 // return: []T
-func (s *hSlice) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(http.Handler) *T / func(http.Handler) (T, bool) / func(http.Handler) (T, error)"
+func (set *myTypeSet) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(MyType) *T / func(MyType) (T, bool) / func(MyType) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -4579,13 +4471,13 @@ func (s *hSlice) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(http.Handler)).Elem() {
+	if in != reflect.TypeOf(new(MyType)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(http.Handler) *T
+		// func(MyType) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -4602,7 +4494,7 @@ func (s *hSlice) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(http.Handler) (T, bool)
+			// func(MyType) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -4610,7 +4502,7 @@ func (s *hSlice) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(http.Handler) (T, error)
+			// func(MyType) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -4624,8 +4516,8 @@ func (s *hSlice) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 
-	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
-	s.ForEach(func(item http.Handler) {
+	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
+	set.ForEach(func(item MyType) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -4634,308 +4526,383 @@ func (s *hSlice) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (s *hSlice) DoUntil(f func(http.Handler) bool) int {
-	for idx, item := range s.elements {
-		if f(item) {
-			return idx
+func (set *myTypeSet) Reduce(f func(MyType, MyType) MyType) MyType {
+	if set.IsEmpty() {
+		var defaultVal MyType
+		return defaultVal
+	}
+	var ret MyType
+	first := true
+	for item := range set.elements {
+		if first {
+			ret = item
+			first = false
+			continue
 		}
+		ret = f(ret, item)
 	}
-	return -1
+	return ret
 }
 
-func (s *hSlice) DoWhile(f func(http.Handler) bool) int {
-	for idx, item := range s.elements {
-		if !f(item) {
-			return idx
-		}
+func (set *myTypeSet) Fold(init MyType, f func(MyType, MyType) MyType) MyType {
+	if set.IsEmpty() {
+		return init
 	}
-	return -1
+	for item := range set.elements {
+		init = f(init, item)
+	}
+	return init
 }
 
-func (s *hSlice) DoUntilError(f func(http.Handler) error) error {
-	for _, item := range s.elements {
-		if err := f(item); err != nil {
-			return err
-		}
+func (set *myTypeSet) String() string {
+	return fmt.Sprint(set.ToSlice())
+}
+
+func (set myTypeSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.ToSlice())
+}
+
+func (set *myTypeSet) UnmarshalJSON(b []byte) error {
+	s := make([]MyType, 0)
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
 	}
+	*set = *newMyTypeSetFromSlice(s)
 	return nil
 }
 
-func (s *hSlice) All(f func(http.Handler) bool) bool {
-	for _, item := range s.elements {
-		if !f(item) {
-			return false
-		}
+func (c *c) getAbc() string {
+	if c == nil {
+		var defaultVal string
+		return defaultVal
 	}
-	return true
+	return c.abc
 }
 
-func (s *hSlice) Any(f func(http.Handler) bool) bool {
-	for _, item := range s.elements {
-		if f(item) {
-			return true
-		}
+func (c *c) GetDef() *int {
+	if c == nil {
+		var defaultVal *int
+		return defaultVal
 	}
-	return false
+	return c.Def
 }
 
-func (s *hSlice) Reduce(f func(http.Handler, http.Handler) http.Handler) http.Handler {
-	if s.IsEmpty() {
-		var defaultVal http.Handler
+func (c *c) TakeTime() t.Time {
+	if c == nil {
+		var defaultVal t.Time
 		return defaultVal
 	}
-	ret := s.elements[0]
-	for _, item := range s.elements[1:] {
-		ret = f(ret, item)
+	return c.a
+}
+
+func (c *c) getB() []string {
+	if c == nil {
+		var defaultVal []string
+		return defaultVal
 	}
-	return ret
+	return c.b
 }
 
-func (s *hSlice) Fold(init http.Handler, f func(http.Handler, http.Handler) http.Handler) http.Handler {
-	if s.IsEmpty() {
-		return init
+func (c *c) getBb() [3]string {
+	if c == nil {
+		var defaultVal [3]string
+		return defaultVal
 	}
-	for _, item := range s.elements {
-		init = f(init, item)
+	return c.bb
+}
+
+func (c *c) getC() map[int]string {
+	if c == nil {
+		var defaultVal map[int]string
+		return defaultVal
 	}
-	return init
+	return c.c
 }
 
-func (s *hSlice) String() string {
-	return fmt.Sprint(s.elements)
+func (c *c) getD() chan int {
+	if c == nil {
+		var defaultVal chan int
+		return defaultVal
+	}
+	return c.d
 }
 
-func (s hSlice) MarshalJSON() ([]byte, error) {
-	return json.Marshal(s.elements)
+func (c *c) getE() chan<- int {
+	if c == nil {
+		var defaultVal chan<- int
+		return defaultVal
+	}
+	return c.e
 }
 
-func (s *hSlice) UnmarshalJSON(b []byte) error {
-	return json.Unmarshal(b, &s.elements)
+func (c *c) getF() <-chan int {
+	if c == nil {
+		var defaultVal <-chan int
+		return defaultVal
+	}
+	return c.f
 }
 
-type PSet struct {
-	elements map[plugin.Plugin]struct{}
+func (c *c) getFf() func(int, string, c, d t.Time) (a, b bool, e error) {
+	if c == nil {
+		var defaultVal func(int, string, c, d t.Time) (a, b bool, e error)
+		return defaultVal
+	}
+	return c.ff
 }
 
-func NewPSet(capacity int) *PSet {
-	set := new(PSet)
-	if capacity > 0 {
-		set.elements = make(map[plugin.Plugin]struct{}, capacity)
-	} else {
-		set.elements = make(map[plugin.Plugin]struct{})
+func (b *b) getC() *c {
+	if b == nil {
+		var defaultVal *c
+		return defaultVal
 	}
-	return set
+	return b.c
 }
 
-func NewPSetFromSlice(items []plugin.Plugin) *PSet {
-	set := NewPSet(len(items))
-	for _, item := range items {
-		set.Append(item)
+func (b *b) GetC() *c {
+	if b == nil {
+		var defaultVal *c
+		return defaultVal
 	}
-	return set
+	return b.C
 }
 
-func (set *PSet) Len() int {
-	if set == nil {
-		return 0
+func (a *AA) getB() *b {
+	if a == nil {
+		var defaultVal *b
+		return defaultVal
 	}
-	return len(set.elements)
+	return a.b
 }
 
-func (set *PSet) IsEmpty() bool {
-	return set.Len() == 0
+func (a *AA) GetB() *b {
+	if a == nil {
+		var defaultVal *b
+		return defaultVal
+	}
+	return a.B
 }
 
-func (set *PSet) ToSlice() []plugin.Plugin {
-	if set == nil {
-		return nil
+func (a *AA) getC() *c {
+	if a == nil {
+		var defaultVal *c
+		return defaultVal
 	}
-	s := make([]plugin.Plugin, 0, set.Len())
-	set.ForEach(func(item plugin.Plugin) {
-		s = append(s, item)
-	})
-	return s
+	return a.c
 }
 
-func (set *PSet) Append(keys ...plugin.Plugin) {
-	for _, key := range keys {
-		set.elements[key] = struct{}{}
+func (a *AA) GetC() *c {
+	if a == nil {
+		var defaultVal *c
+		return defaultVal
 	}
+	return a.C
 }
 
-func (set *PSet) Clear() {
-	set.elements = make(map[plugin.Plugin]struct{})
+type NotComparableTypeSlice struct {
+	elements []NotComparableType
 }
 
-func (set *PSet) Clone() *PSet {
-	cloned := NewPSet(set.Len())
-	for item := range set.elements {
-		cloned.elements[item] = struct{}{}
+func NewNotComparableTypeSlice(capacity int) *NotComparableTypeSlice {
+	return &NotComparableTypeSlice{
+		elements: make([]NotComparableType, 0, capacity),
 	}
-	return cloned
 }
 
-func (set *PSet) Difference(another *PSet) *PSet {
-	difference := NewPSet(0)
-	set.ForEach(func(item plugin.Plugin) {
-		if !another.Contains(item) {
-			difference.Append(item)
-		}
-	})
-	return difference
+func NewNotComparableTypeSliceFromSlice(slice []NotComparableType) *NotComparableTypeSlice {
+	return &NotComparableTypeSlice{
+		elements: slice,
+	}
 }
 
-func (set *PSet) Equal(another *PSet) bool {
-	if set.Len() != another.Len() {
-		return false
+func (s *NotComparableTypeSlice) Len() int {
+	if s == nil {
+		return 0
 	}
-	for item := range set.elements {
-		if !another.Contains(item) {
-			return false
-		}
+	return len(s.elements)
+}
+
+func (s *NotComparableTypeSlice) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+func (s *NotComparableTypeSlice) Append(items ...NotComparableType) {
+	s.elements = append(s.elements, items...)
+}
+
+func (s *NotComparableTypeSlice) Clone() *NotComparableTypeSlice {
+	cloned := &NotComparableTypeSlice{
+		elements: make([]NotComparableType, s.Len()),
 	}
-	return true
+	copy(cloned.elements, s.elements)
+	return cloned
 }
 
-func (set *PSet) Intersect(another *PSet) *PSet {
-	intersection := NewPSet(0)
-	if set.Len() < another.Len() {
-		for item := range set.elements {
-			if another.Contains(item) {
-				intersection.Append(item)
-			}
-		}
-	} else {
-		for item := range another.elements {
-			if set.Contains(item) {
-				intersection.Append(item)
-			}
-		}
+func (s *NotComparableTypeSlice) ToSlice() []NotComparableType {
+	slice := make([]NotComparableType, s.Len())
+	copy(slice, s.elements)
+	return slice
+}
+
+func (s *NotComparableTypeSlice) ToSliceRef() []NotComparableType {
+	return s.elements
+}
+
+func (s *NotComparableTypeSlice) Clear() {
+	s.elements = s.elements[:0]
+}
+
+func (s *NotComparableTypeSlice) Insert(idx int, items ...NotComparableType) {
+	if idx < 0 {
+		idx += s.Len()
 	}
-	return intersection
+	if l := len(s.elements) + len(items); l > cap(s.elements) {
+		// reallocate
+		result := make([]NotComparableType, l)
+		copy(result, s.elements[:idx])
+		copy(result[idx:], items)
+		copy(result[idx+len(items):], s.elements[idx:])
+		s.elements = result
+		return
+	}
+
+	l := s.Len()
+	s.elements = append(s.elements, items...)
+	copy(s.elements[idx+len(items):], s.elements[idx:l])
+	copy(s.elements[idx:], items)
 }
 
-func (set *PSet) Union(another *PSet) *PSet {
-	union := set.Clone()
-	union.InPlaceUnion(another)
-	return union
+func (s *NotComparableTypeSlice) Remove(idx int) {
+	if idx < 0 {
+		idx += s.Len()
+	}
+	s.elements = append(s.elements[:idx], s.elements[idx+1:]...)
 }
 
-func (set *PSet) InPlaceUnion(another *PSet) {
-	another.ForEach(func(item plugin.Plugin) {
-		set.Append(item)
-	})
+func (s *NotComparableTypeSlice) RemoveRange(from, to int) {
+	if from < 0 {
+		from += s.Len()
+	}
+	if to < 0 {
+		to += s.Len()
+	}
+	s.elements = append(s.elements[:from], s.elements[to+1:]...)
 }
 
-func (set *PSet) IsProperSubsetOf(another *PSet) bool {
-	return !set.Equal(another) && set.IsSubsetOf(another)
+func (s *NotComparableTypeSlice) RemoveFrom(idx int) {
+	if idx < 0 {
+		idx += s.Len()
+	}
+	s.elements = s.elements[:idx]
 }
 
-func (set *PSet) IsProperSupersetOf(another *PSet) bool {
-	return !set.Equal(another) && set.IsSupersetOf(another)
+func (s *NotComparableTypeSlice) RemoveTo(idx int) {
+	if idx < 0 {
+		idx += s.Len()
+	}
+	s.elements = s.elements[idx+1:]
 }
 
-func (set *PSet) IsSubsetOf(another *PSet) bool {
-	if set.Len() > another.Len() {
-		return false
+func (s *NotComparableTypeSlice) Concat(another *NotComparableTypeSlice) *NotComparableTypeSlice {
+	result := s.Clone()
+	if another.IsEmpty() {
+		return result
 	}
-	for item := range set.elements {
-		if !another.Contains(item) {
-			return false
-		}
+	result.Append(another.elements...)
+	return result
+}
+
+func (s *NotComparableTypeSlice) InPlaceConcat(another *NotComparableTypeSlice) {
+	if another.IsEmpty() {
+		return
 	}
-	return true
+	s.Append(another.elements...)
 }
 
-func (set *PSet) IsSupersetOf(another *PSet) bool {
-	return another.IsSubsetOf(set)
+func (s *NotComparableTypeSlice) ForEach(f func(NotComparableType)) {
+	if s.IsEmpty() {
+		return
+	}
+	for _, item := range s.elements {
+		f(item)
+	}
 }
 
-func (set *PSet) ForEach(f func(plugin.Plugin)) {
-	if set.IsEmpty() {
+func (s *NotComparableTypeSlice) ForEachWithIndex(f func(int, NotComparableType)) {
+	if s.IsEmpty() {
 		return
 	}
-	for item := range set.elements {
-		f(item)
+	for idx, item := range s.elements {
+		f(idx, item)
 	}
 }
 
-func (set *PSet) Filter(f func(plugin.Plugin) bool) *PSet {
-	result := NewPSet(0)
-	set.ForEach(func(item plugin.Plugin) {
+func (s *NotComparableTypeSlice) Filter(f func(NotComparableType) bool) *NotComparableTypeSlice {
+	result := NewNotComparableTypeSlice(0)
+	for _, item := range s.elements {
 		if f(item) {
 			result.Append(item)
 		}
-	})
+	}
 	return result
 }
 
-func (set *PSet) Remove(key plugin.Plugin) {
-	delete(set.elements, key)
-}
-
-func (set *PSet) Contains(key plugin.Plugin) bool {
-	_, ok := set.elements[key]
-	return ok
-}
-
-func (set *PSet) ContainsAny(keys ...plugin.Plugin) bool {
-	for _, key := range keys {
-		if set.Contains(key) {
-			return true
-		}
+func (s *NotComparableTypeSlice) Index(idx int) *NotComparableType {
+	if idx < 0 {
+		idx += s.Len()
 	}
-	return false
+	return &s.elements[idx]
 }
 
-func (set *PSet) ContainsAll(keys ...plugin.Plugin) bool {
-	for _, key := range keys {
-		if !set.Contains(key) {
-			return false
-		}
+func (s *NotComparableTypeSlice) IndexRange(from, to int) *NotComparableTypeSlice {
+	if from < 0 {
+		from += s.Len()
 	}
-	return true
+	if to < 0 {
+		to += s.Len()
+	}
+	return NewNotComparableTypeSliceFromSlice(s.elements[from:to])
 }
 
-func (set *PSet) DoUntilError(f func(plugin.Plugin) error) error {
-	for item := range set.elements {
-		if err := f(item); err != nil {
-			return err
-		}
+func (s *NotComparableTypeSlice) IndexFrom(idx int) *NotComparableTypeSlice {
+	if idx < 0 {
+		idx += s.Len()
 	}
-	return nil
+	return NewNotComparableTypeSliceFromSlice(s.elements[idx:])
 }
 
-func (set *PSet) All(f func(plugin.Plugin) bool) bool {
-	for item := range set.elements {
-		if !f(item) {
-			return false
-		}
+func (s *NotComparableTypeSlice) IndexTo(idx int) *NotComparableTypeSlice {
+	if idx < 0 {
+		idx += s.Len()
 	}
-	return true
+	return NewNotComparableTypeSliceFromSlice(s.elements[:idx])
 }
 
-func (set *PSet) Any(f func(plugin.Plugin) bool) bool {
-	for item := range set.elements {
-		if f(item) {
-			return true
+func (s *NotComparableTypeSlice) FindBy(f func(NotComparableType) bool) int {
+	if s.IsEmpty() {
+		return -1
+	}
+	for idx, n := range s.elements {
+		if f(n) {
+			return idx
 		}
 	}
-	return false
+	return -1
 }
 
-func (set *PSet) FindBy(f func(plugin.Plugin) bool) *plugin.Plugin {
-	for item := range set.elements {
-		if f(item) {
-			return &item
+func (s *NotComparableTypeSlice) FindLastBy(f func(NotComparableType) bool) int {
+	for idx := s.Len() - 1; idx >= 0; idx-- {
+		if f(s.elements[idx]) {
+			return idx
 		}
 	}
-	return nil
+	return -1
 }
 
-func (set *PSet) CountBy(f func(plugin.Plugin) bool) int {
-	count := 0
-	set.ForEach(func(item plugin.Plugin) {
+func (s *NotComparableTypeSlice) CountBy(f func(NotComparableType) bool) uint {
+	count := uint(0)
+	s.ForEach(func(item NotComparableType) {
 		if f(item) {
 			count++
 		}
@@ -4943,9 +4910,9 @@ func (set *PSet) CountBy(f func(plugin.Plugin) bool) int {
 	return count
 }
 
-func (set *PSet) GroupByBool(f func(plugin.Plugin) bool) (trueGroup *PSet, falseGroup *PSet) {
-	trueGroup, falseGroup = NewPSet(0), NewPSet(0)
-	set.ForEach(func(item plugin.Plugin) {
+func (s *NotComparableTypeSlice) GroupByBool(f func(NotComparableType) bool) (trueGroup, falseGroup *NotComparableTypeSlice) {
+	trueGroup, falseGroup = NewNotComparableTypeSlice(0), NewNotComparableTypeSlice(0)
+	s.ForEach(func(item NotComparableType) {
 		if f(item) {
 			trueGroup.Append(item)
 		} else {
@@ -4955,13 +4922,13 @@ func (set *PSet) GroupByBool(f func(plugin.Plugin) bool) (trueGroup *PSet, false
 	return trueGroup, falseGroup
 }
 
-func (set *PSet) GroupByStr(f func(plugin.Plugin) string) map[string]*PSet {
-	groups := make(map[string]*PSet)
-	set.ForEach(func(item plugin.Plugin) {
+func (s NotComparableTypeSlice) GroupByStr(f func(NotComparableType) string) map[string]*NotComparableTypeSlice {
+	groups := make(map[string]*NotComparableTypeSlice)
+	s.ForEach(func(item NotComparableType) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewPSet(0)
+			group = NewNotComparableTypeSlice(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4969,13 +4936,13 @@ func (set *PSet) GroupByStr(f func(plugin.Plugin) string) map[string]*PSet {
 	return groups
 }
 
-func (set *PSet) GroupByInt(f func(plugin.Plugin) int) map[int]*PSet {
-	groups := make(map[int]*PSet)
-	set.ForEach(func(item plugin.Plugin) {
+func (s NotComparableTypeSlice) GroupByInt(f func(NotComparableType) int) map[int]*NotComparableTypeSlice {
+	groups := make(map[int]*NotComparableTypeSlice)
+	s.ForEach(func(item NotComparableType) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewPSet(0)
+			group = NewNotComparableTypeSlice(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4983,13 +4950,13 @@ func (set *PSet) GroupByInt(f func(plugin.Plugin) int) map[int]*PSet {
 	return groups
 }
 
-func (set *PSet) GroupBy(f func(plugin.Plugin) interface{}) map[interface{}]*PSet {
-	groups := make(map[interface{}]*PSet)
-	set.ForEach(func(item plugin.Plugin) {
+func (s *NotComparableTypeSlice) GroupBy(f func(NotComparableType) interface{}) map[interface{}]*NotComparableTypeSlice {
+	groups := make(map[interface{}]*NotComparableTypeSlice)
+	s.ForEach(func(item NotComparableType) {
 		key := f(item)
 		group := groups[key]
 		if group == nil {
-			group = NewPSet(0)
+			group = NewNotComparableTypeSlice(0)
 			groups[key] = group
 		}
 		group.Append(item)
@@ -4997,10 +4964,10 @@ func (set *PSet) GroupBy(f func(plugin.Plugin) interface{}) map[interface{}]*PSe
 	return groups
 }
 
-// f: func(plugin.Plugin) T
+// f: func(NotComparableType) T
 // return: []T
-func (set *PSet) Map(f interface{}) interface{} {
-	expected := "f should be func(plugin.Plugin)T"
+func (s *NotComparableTypeSlice) Map(f interface{}) interface{} {
+	expected := "f should be func(NotComparableType)T"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -5009,7 +4976,7 @@ func (set *PSet) Map(f interface{}) interface{} {
 	if ft.NumIn() != 1 {
 		panic(expected)
 	}
-	elemType := reflect.TypeOf(new(plugin.Plugin)).Elem()
+	elemType := reflect.TypeOf(new(NotComparableType)).Elem()
 	if ft.In(0) != elemType {
 		panic(expected)
 	}
@@ -5017,19 +4984,19 @@ func (set *PSet) Map(f interface{}) interface{} {
 		panic(expected)
 	}
 	outType := ft.Out(0)
-	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item plugin.Plugin) {
+	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
+	s.ForEach(func(item NotComparableType) {
 		result = reflect.Append(result, fVal.Call([]reflect.Value{reflect.ValueOf(item)})[0])
 	})
 	return result.Interface()
 }
 
-// f: func(plugin.Plugin) *T
-//    func(plugin.Plugin) (T, bool)
-//    func(plugin.Plugin) (T, error)
+// f: func(NotComparableType) *T
+//    func(NotComparableType) (T, bool)
+//    func(NotComparableType) (T, error)
 // return: []T
-func (set *PSet) FilterMap(f interface{}) interface{} {
-	expected := "f should be func(plugin.Plugin) *T / func(plugin.Plugin) (T, bool) / func(plugin.Plugin) (T, error)"
+func (s *NotComparableTypeSlice) FilterMap(f interface{}) interface{} {
+	expected := "f should be func(NotComparableType) *T / func(NotComparableType) (T, bool) / func(NotComparableType) (T, error)"
 	ft := reflect.TypeOf(f)
 	fVal := reflect.ValueOf(f)
 	if ft.Kind() != reflect.Func {
@@ -5039,13 +5006,13 @@ func (set *PSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 	in := ft.In(0)
-	if in != reflect.TypeOf(new(plugin.Plugin)).Elem() {
+	if in != reflect.TypeOf(new(NotComparableType)).Elem() {
 		panic(expected)
 	}
 	var outType reflect.Type
 	var filter func([]reflect.Value) *reflect.Value
 	if ft.NumOut() == 1 {
-		// func(plugin.Plugin) *T
+		// func(NotComparableType) *T
 		outType = ft.Out(0)
 		if outType.Kind() != reflect.Ptr {
 			panic(expected)
@@ -5062,7 +5029,7 @@ func (set *PSet) FilterMap(f interface{}) interface{} {
 		outType = ft.Out(0)
 		checker := ft.Out(1)
 		if checker == reflect.TypeOf(true) {
-			// func(plugin.Plugin) (T, bool)
+			// func(NotComparableType) (T, bool)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].Interface().(bool) {
 					return &values[0]
@@ -5070,7 +5037,7 @@ func (set *PSet) FilterMap(f interface{}) interface{} {
 				return nil
 			}
 		} else if checker.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			// func(plugin.Plugin) (T, error)
+			// func(NotComparableType) (T, error)
 			filter = func(values []reflect.Value) *reflect.Value {
 				if values[1].IsNil() {
 					return &values[0]
@@ -5084,8 +5051,8 @@ func (set *PSet) FilterMap(f interface{}) interface{} {
 		panic(expected)
 	}
 
-	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, set.Len())
-	set.ForEach(func(item plugin.Plugin) {
+	result := reflect.MakeSlice(reflect.SliceOf(outType), 0, s.Len())
+	s.ForEach(func(item NotComparableType) {
 		ret := fVal.Call([]reflect.Value{reflect.ValueOf(item)})
 		if val := filter(ret); val != nil {
 			result = reflect.Append(result, *val)
@@ -5094,48 +5061,81 @@ func (set *PSet) FilterMap(f interface{}) interface{} {
 	return result.Interface()
 }
 
-func (set *PSet) Reduce(f func(plugin.Plugin, plugin.Plugin) plugin.Plugin) plugin.Plugin {
-	if set.IsEmpty() {
-		var defaultVal plugin.Plugin
-		return defaultVal
+func (s *NotComparableTypeSlice) DoUntil(f func(NotComparableType) bool) int {
+	for idx, item := range s.elements {
+		if f(item) {
+			return idx
+		}
 	}
-	var ret plugin.Plugin
-	first := true
-	for item := range set.elements {
-		if first {
-			ret = item
-			first = false
-			continue
+	return -1
+}
+
+func (s *NotComparableTypeSlice) DoWhile(f func(NotComparableType) bool) int {
+	for idx, item := range s.elements {
+		if !f(item) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (s *NotComparableTypeSlice) DoUntilError(f func(NotComparableType) error) error {
+	for _, item := range s.elements {
+		if err := f(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NotComparableTypeSlice) All(f func(NotComparableType) bool) bool {
+	for _, item := range s.elements {
+		if !f(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *NotComparableTypeSlice) Any(f func(NotComparableType) bool) bool {
+	for _, item := range s.elements {
+		if f(item) {
+			return true
 		}
+	}
+	return false
+}
+
+func (s *NotComparableTypeSlice) Reduce(f func(NotComparableType, NotComparableType) NotComparableType) NotComparableType {
+	if s.IsEmpty() {
+		var defaultVal NotComparableType
+		return defaultVal
+	}
+	ret := s.elements[0]
+	for _, item := range s.elements[1:] {
 		ret = f(ret, item)
 	}
 	return ret
 }
 
-func (set *PSet) Fold(init plugin.Plugin, f func(plugin.Plugin, plugin.Plugin) plugin.Plugin) plugin.Plugin {
-	if set.IsEmpty() {
+func (s *NotComparableTypeSlice) Fold(init NotComparableType, f func(NotComparableType, NotComparableType) NotComparableType) NotComparableType {
+	if s.IsEmpty() {
 		return init
 	}
-	for item := range set.elements {
+	for _, item := range s.elements {
 		init = f(init, item)
 	}
 	return init
 }
 
-func (set *PSet) String() string {
-	return fmt.Sprint(set.ToSlice())
+func (s *NotComparableTypeSlice) String() string {
+	return fmt.Sprint(s.elements)
 }
 
-func (set PSet) MarshalJSON() ([]byte, error) {
-	return json.Marshal(set.ToSlice())
+func (s NotComparableTypeSlice) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.elements)
 }
 
-func (set *PSet) UnmarshalJSON(b []byte) error {
-	s := make([]plugin.Plugin, 0)
-	err := json.Unmarshal(b, &s)
-	if err != nil {
-		return err
-	}
-	*set = *NewPSetFromSlice(s)
-	return nil
+func (s *NotComparableTypeSlice) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &s.elements)
 }
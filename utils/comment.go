@@ -4,14 +4,18 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"unicode"
 )
 
 type DeriveComment struct {
 	Plugin     string
 	OptionsStr string
+	// byte offset of OptionsStr in the matched comment
+	OptionsOffset int
 }
 
 func MatchDeriveComment(cmt string) (*DeriveComment, error) {
+	origin := cmt
 	cmt = strings.TrimPrefix(cmt, "//")
 	cmt = strings.TrimSpace(cmt)
 	if !strings.HasPrefix(cmt, "derive-") {
@@ -24,7 +28,10 @@ func MatchDeriveComment(cmt string) (*DeriveComment, error) {
 		dc.Plugin = cmt
 	} else {
 		dc.Plugin = cmt[:splitIdx]
-		dc.OptionsStr = strings.TrimSpace(cmt[splitIdx+1:])
+		optsStr := cmt[splitIdx+1:]
+		dc.OptionsStr = strings.TrimSpace(optsStr)
+		// options string is at the end of origin comment, except trailing spaces
+		dc.OptionsOffset = len(strings.TrimRightFunc(origin, unicode.IsSpace)) - len(dc.OptionsStr)
 	}
 	if !ValidateIdentName(dc.Plugin) {
 		return nil, fmt.Errorf("invalid plugin name %#v", dc.Plugin)
@@ -2,10 +2,15 @@
 package utils
 
 import (
+	"bytes"
 	"go/ast"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/olekukonko/tablewriter"
@@ -53,6 +58,11 @@ type Str2 = string
 
 const HeaderComment = "// Code generated by https://github.com/nextzhou/goderive. DO NOT EDIT.\n\n"
 
+// IsGeneratedFile reports whether src is generated by goderive.
+func IsGeneratedFile(src []byte) bool {
+	return bytes.HasPrefix(src, []byte(strings.TrimRight(HeaderComment, "\n")))
+}
+
 func ToExported(ident string) string {
 	if len(ident) == 0 {
 		return ident
@@ -261,3 +271,71 @@ func ExprTypeStr(expr ast.Expr) string {
 	s = strings.TrimSuffix(s, "Type")
 	return s
 }
+
+// WriteTempFile writes data to a hidden temporary file beside filename, and returns the name of the temporary file.
+func WriteTempFile(filename string, data []byte, perm os.FileMode) (string, error) {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := ioutil.TempFile(dir, "."+base+".tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpName := f.Name()
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, perm)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return "", err
+	}
+	return tmpName, nil
+}
+
+// ParallelDo calls f with index from 0 to n-1 in at most workers goroutines, and waits for all of them.
+// If f panics, the first panic is raised again in the calling goroutine after all calls are done.
+func ParallelDo(workers, n int, f func(idx int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for idx := 0; idx < n; idx++ {
+			f(idx)
+		}
+		return
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var panicked interface{}
+	var panicOnce sync.Once
+	call := func(idx int) {
+		defer func() {
+			if r := recover(); r != nil {
+				panicOnce.Do(func() { panicked = r })
+			}
+		}()
+		f(idx)
+	}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				call(idx)
+			}
+		}()
+	}
+	for idx := 0; idx < n; idx++ {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+	if panicked != nil {
+		panic(panicked)
+	}
+}
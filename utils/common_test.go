@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"sync/atomic"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParallelDo(t *testing.T) {
+	Convey("parallel do", t, func() {
+		for _, workers := range []int{0, 1, 4, 100} {
+			results := make([]int, 50)
+			var calls int32
+			ParallelDo(workers, len(results), func(idx int) {
+				atomic.AddInt32(&calls, 1)
+				results[idx] = idx * idx
+			})
+			So(calls, ShouldEqual, 50)
+			for idx, r := range results {
+				So(r, ShouldEqual, idx*idx)
+			}
+		}
+
+		Convey("panics are raised in the caller", func() {
+			for _, workers := range []int{1, 4} {
+				So(func() {
+					ParallelDo(workers, 50, func(idx int) {
+						if idx == 10 {
+							panic("bug")
+						}
+					})
+				}, ShouldPanicWith, "bug")
+			}
+		})
+	})
+}
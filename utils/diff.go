@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const diffContextLines = 3
+
+type diffOp uint8
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+type diffLine struct {
+	Op   diffOp
+	Text string
+}
+
+// UnifiedDiff returns the unified diff between oldSrc and newSrc, or an empty string if they are equal.
+func UnifiedDiff(oldName, newName string, oldSrc, newSrc []byte) string {
+	if bytes.Equal(oldSrc, newSrc) {
+		return ""
+	}
+	lines := diffLines(splitLines(oldSrc), splitLines(newSrc))
+
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", oldName, newName))
+	oldLine, newLine := 1, 1
+	for start := 0; start < len(lines); {
+		// find next changed line
+		first := start
+		for first < len(lines) && lines[first].Op == diffEqual {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		// extend hunk while changes are separated by no more than 2*context equal lines
+		last := first
+		for i := first + 1; i < len(lines) && i-last <= 2*diffContextLines; i++ {
+			if lines[i].Op != diffEqual {
+				last = i
+			}
+		}
+		from := first - diffContextLines
+		if from < start {
+			from = start
+		}
+		to := last + diffContextLines + 1
+		if to > len(lines) {
+			to = len(lines)
+		}
+		// skipped lines are all equal
+		oldLine += from - start
+		newLine += from - start
+		var oldCount, newCount int
+		for _, l := range lines[from:to] {
+			if l.Op != diffInsert {
+				oldCount++
+			}
+			if l.Op != diffDelete {
+				newCount++
+			}
+		}
+		buf.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount)))
+		for _, l := range lines[from:to] {
+			switch l.Op {
+			case diffEqual:
+				buf.WriteByte(' ')
+			case diffDelete:
+				buf.WriteByte('-')
+			case diffInsert:
+				buf.WriteByte('+')
+			}
+			buf.WriteString(l.Text)
+			if !strings.HasSuffix(l.Text, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		oldLine += oldCount
+		newLine += newCount
+		start = to
+	}
+	return buf.String()
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		// empty range starts at the line before
+		return fmt.Sprintf("%d,0", line-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// splitLines splits src into lines ending with line feeds, except the last one if src doesn't end with a line feed.
+// So a missing line feed at the end makes a difference.
+func splitLines(src []byte) []string {
+	var lines []string
+	for len(src) > 0 {
+		idx := bytes.IndexByte(src, '\n')
+		if idx < 0 {
+			lines = append(lines, string(src))
+			break
+		}
+		lines = append(lines, string(src[:idx+1]))
+		src = src[idx+1:]
+	}
+	return lines
+}
+
+// Myers' O((N+M)D) difference algorithm
+func diffLines(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	max := n + m
+	v := make([]int, 2*max+3)
+	offset := max + 1
+	// trace[d] holds v[-d-1 .. d+1] before the d-th round
+	var trace [][]int
+found:
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break found
+			}
+		}
+	}
+
+	// backtrack
+	var reversed []diffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d+1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffLine{Op: diffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, diffLine{Op: diffInsert, Text: b[y-1]})
+			} else {
+				reversed = append(reversed, diffLine{Op: diffDelete, Text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	lines := make([]diffLine, len(reversed))
+	for i, l := range reversed {
+		lines[len(reversed)-1-i] = l
+	}
+	return lines
+}
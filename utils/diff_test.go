@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	Convey("unified diff", t, func() {
+		Convey("equal", func() {
+			So(UnifiedDiff("a", "b", []byte("x\ny\n"), []byte("x\ny\n")), ShouldBeEmpty)
+		})
+		Convey("modified", func() {
+			oldSrc := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n")
+			newSrc := []byte("1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n")
+			So(UnifiedDiff("a", "b", oldSrc, newSrc), ShouldEqual, `--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`)
+		})
+		Convey("separated hunks", func() {
+			oldSrc := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n")
+			newSrc := []byte("0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n12\n")
+			So(UnifiedDiff("a", "b", oldSrc, newSrc), ShouldEqual, `--- a
++++ b
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -8,5 +9,4 @@
+ 8
+ 9
+ 10
+-11
+ 12
+`)
+		})
+		Convey("new and removed file", func() {
+			So(UnifiedDiff("a", "b", nil, []byte("x\n")), ShouldEqual, "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n")
+			So(UnifiedDiff("a", "b", []byte("x\ny\n"), nil), ShouldEqual, "--- a\n+++ b\n@@ -1,2 +0,0 @@\n-x\n-y\n")
+		})
+		Convey("no newline at end of file", func() {
+			So(UnifiedDiff("a", "b", []byte("x\ny"), []byte("x\ny\n")), ShouldEqual,
+				"--- a\n+++ b\n@@ -1,2 +1,2 @@\n x\n-y\n\\ No newline at end of file\n+y\n")
+			So(UnifiedDiff("a", "b", []byte("x\n"), []byte("x\ny")), ShouldEqual,
+				"--- a\n+++ b\n@@ -1 +1,2 @@\n x\n+y\n\\ No newline at end of file\n")
+			So(UnifiedDiff("a", "b", []byte("x\nz"), []byte("y\nz")), ShouldEqual,
+				"--- a\n+++ b\n@@ -1,2 +1,2 @@\n-x\n+y\n z\n\\ No newline at end of file\n")
+		})
+	})
+}
@@ -1,6 +1,14 @@
 package utils
 
-import "fmt"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"sort"
+	"strings"
+)
 
 type InvalidIdentError struct {
 	Type  string
@@ -53,6 +61,27 @@ func (e *UnsupportedError) Error() string {
 	return fmt.Sprintf("unsupported %s %#v", e.Type, e.Idents)
 }
 
+// SkippedPluginError is the warning for annotations of plugins disabled in command line
+type SkippedPluginError struct {
+	Plugin string
+}
+
+func (e *SkippedPluginError) Error() string {
+	return "skipped, the plugin is disabled"
+}
+
+// ConflictingPackageError occurs when types of different packages would be generated into the same file
+type ConflictingPackageError struct {
+	Packages []string
+	Files    []string
+	Output   string
+}
+
+func (e *ConflictingPackageError) Error() string {
+	return fmt.Sprintf("found packages %s (%s) and %s (%s), both generated into %#v",
+		e.Packages[0], e.Files[0], e.Packages[1], e.Files[1], e.Output)
+}
+
 type ArgNotSingleValueError struct {
 	ArgKey string
 }
@@ -87,3 +116,225 @@ type UnmatchedError struct {
 func (e *UnmatchedError) Error() string {
 	return fmt.Sprintf("unmatched %s %#v, expected %#v", e.Ident, e.Got, e.Expected)
 }
+
+type NotGeneratedFileError struct {
+	Filename string
+	Action   string
+}
+
+func (e *NotGeneratedFileError) Error() string {
+	return fmt.Sprintf("refuse to %s %#v which is not generated by goderive, use --force to override", e.Action, e.Filename)
+}
+
+// SkippedPluginOutputError occurs when an output which would hold code of disabled plugins is to be changed
+type SkippedPluginOutputError struct {
+	Filename string
+	Action   string
+	Plugins  []string
+}
+
+func (e *SkippedPluginOutputError) Error() string {
+	return fmt.Sprintf("refuse to %s %#v without code of disabled plugins %s, run with them enabled to regenerate it",
+		e.Action, e.Filename, strings.Join(e.Plugins, ", "))
+}
+
+// InvalidCodeError reports source code generated by a plugin that can't be formatted.
+type InvalidCodeError struct {
+	Plugin string
+	Type   string
+	// position of the derive comment of the type
+	Source token.Position
+	// position in the generated code
+	Line   int
+	Column int
+	Msg    string
+	// numbered lines around the error
+	Context string
+	// broken generated code
+	Src []byte
+	// output file the code was generated for
+	Output string
+	// file the broken code was written to, if any
+	DebugFile string
+}
+
+func (e *InvalidCodeError) Error() string {
+	buf := bytes.NewBuffer(nil)
+	if e.Source.IsValid() {
+		buf.WriteString(fmt.Sprintf("%s: type %s: plugin %s generated invalid code", e.Source, e.Type, e.Plugin))
+	} else {
+		buf.WriteString("invalid generated code")
+	}
+	if e.Line > 0 {
+		buf.WriteString(fmt.Sprintf(" at %d:%d", e.Line, e.Column))
+	}
+	buf.WriteString(": " + e.Msg)
+	if e.Context != "" {
+		buf.WriteString("\n" + e.Context)
+	}
+	if e.DebugFile != "" {
+		buf.WriteString(fmt.Sprintf("generated code was written to %s", e.DebugFile))
+	}
+	return buf.String()
+}
+
+// PositionedError attaches the source position, and the derived type and plugin if known, to an error.
+type PositionedError struct {
+	Pos    token.Position
+	Type   string
+	Plugin string
+	Err    error
+}
+
+func (e *PositionedError) Error() string {
+	buf := bytes.NewBuffer(nil)
+	if e.Pos.IsValid() {
+		buf.WriteString(e.Pos.String() + ": ")
+	}
+	if e.Type != "" {
+		buf.WriteString(fmt.Sprintf("type %s: ", e.Type))
+	}
+	if e.Plugin != "" {
+		buf.WriteString(fmt.Sprintf("plugin %s: ", e.Plugin))
+	}
+	buf.WriteString(e.Err.Error())
+	return buf.String()
+}
+
+// Positioned attaches position, type and plugin to err, the ones already attached are kept.
+func Positioned(err error, pos token.Position, typ, plugin string) *PositionedError {
+	positioned := new(PositionedError)
+	if pe, ok := err.(*PositionedError); ok {
+		*positioned = *pe
+	} else {
+		positioned.Err = err
+	}
+	if !positioned.Pos.IsValid() {
+		positioned.Pos = pos
+	}
+	if positioned.Type == "" {
+		positioned.Type = typ
+	}
+	if positioned.Plugin == "" {
+		positioned.Plugin = plugin
+	}
+	return positioned
+}
+
+// ErrorList collects errors, to report all of them at once.
+type ErrorList []error
+
+// Add appends err to the list, nested error lists are flattened.
+func (list *ErrorList) Add(err error) {
+	switch e := err.(type) {
+	case nil:
+	case ErrorList:
+		for _, item := range e {
+			list.Add(item)
+		}
+	case scanner.ErrorList:
+		for _, item := range e {
+			*list = append(*list, &PositionedError{Pos: item.Pos, Err: errors.New(item.Msg)})
+		}
+	case *scanner.Error:
+		*list = append(*list, &PositionedError{Pos: e.Pos, Err: errors.New(e.Msg)})
+	default:
+		*list = append(*list, err)
+	}
+}
+
+// Sort sorts errors by position, errors without position are placed at the end.
+func (list ErrorList) Sort() {
+	sort.SliceStable(list, func(i, j int) bool {
+		return PositionLess(ErrorPosition(list[i]), ErrorPosition(list[j]))
+	})
+}
+
+// Err returns nil if the list is empty.
+func (list ErrorList) Err() error {
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+func (list ErrorList) Error() string {
+	msgs := make([]string, 0, len(list))
+	for _, err := range list {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ErrorPosition returns the source position of err if known.
+func ErrorPosition(err error) token.Position {
+	switch e := err.(type) {
+	case *PositionedError:
+		return e.Pos
+	case *InvalidCodeError:
+		return e.Source
+	case *scanner.Error:
+		return e.Pos
+	case scanner.ErrorList:
+		if len(e) > 0 {
+			return e[0].Pos
+		}
+	}
+	return token.Position{}
+}
+
+// PositionLess orders positions by file name, line and column, invalid positions are the greatest.
+func PositionLess(i, j token.Position) bool {
+	if !i.IsValid() || !j.IsValid() {
+		return i.IsValid() && !j.IsValid()
+	}
+	if i.Filename != j.Filename {
+		return i.Filename < j.Filename
+	}
+	if i.Line != j.Line {
+		return i.Line < j.Line
+	}
+	return i.Column < j.Column
+}
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is the structured form of an error, for machine-readable output.
+type Diagnostic struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Plugin   string `json:"plugin,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// MakeDiagnostic extracts structured fields from err.
+func MakeDiagnostic(err error, severity string) Diagnostic {
+	diag := Diagnostic{Severity: severity, Message: err.Error()}
+	var pos token.Position
+	switch e := err.(type) {
+	case *PositionedError:
+		pos = e.Pos
+		diag.Type, diag.Plugin, diag.Message = e.Type, e.Plugin, e.Err.Error()
+	case *InvalidCodeError:
+		pos = e.Source
+		diag.Type, diag.Plugin, diag.Message = e.Type, e.Plugin, e.Msg
+		if e.Line > 0 {
+			diag.Message = fmt.Sprintf("invalid generated code at %d:%d: %s", e.Line, e.Column, e.Msg)
+		}
+		if e.DebugFile != "" {
+			diag.Message += fmt.Sprintf(" (generated code was written to %s)", e.DebugFile)
+		}
+	default:
+		pos = ErrorPosition(err)
+	}
+	if pos.IsValid() {
+		diag.File, diag.Line, diag.Column = pos.Filename, pos.Line, pos.Column
+	}
+	return diag
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"errors"
+	"go/token"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestErrorList(t *testing.T) {
+	Convey("error list", t, func() {
+		var errs ErrorList
+		So(errs.Err(), ShouldBeNil)
+
+		errs.Add(errors.New("no position"))
+		errs.Add(&PositionedError{Pos: token.Position{Filename: "b.go", Line: 1, Column: 1}, Err: errors.New("b")})
+		errs.Add(ErrorList{
+			&PositionedError{Pos: token.Position{Filename: "a.go", Line: 2, Column: 1}, Err: errors.New("a2")},
+			&PositionedError{Pos: token.Position{Filename: "a.go", Line: 1, Column: 5}, Type: "T", Err: errors.New("a1")},
+		})
+		So(errs, ShouldHaveLength, 4)
+
+		errs.Sort()
+		So(errs.Error(), ShouldEqual, "a.go:1:5: type T: a1\na.go:2:1: a2\nb.go:1:1: b\nno position")
+	})
+}
+
+func TestMakeDiagnostic(t *testing.T) {
+	Convey("diagnostic", t, func() {
+		err := Positioned(&UnexpectedError{Type: "flag", Idents: []string{"Foo"}},
+			token.Position{Filename: "a.go", Line: 3, Column: 4}, "T", "set")
+		So(MakeDiagnostic(err, SeverityError), ShouldResemble, Diagnostic{
+			File: "a.go", Line: 3, Column: 4, Plugin: "set", Type: "T", Severity: SeverityError, Message: `unexpected flag "Foo"`,
+		})
+		So(MakeDiagnostic(errors.New("oops"), SeverityWarning), ShouldResemble, Diagnostic{Severity: SeverityWarning, Message: "oops"})
+	})
+}
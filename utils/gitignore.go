@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// GitIgnore is the patterns of a .gitignore file.
+type GitIgnore struct {
+	// directory of the .gitignore file, which patterns are relative to
+	Dir      string
+	patterns []gitIgnorePattern
+}
+
+type gitIgnorePattern struct {
+	glob    string
+	negated bool
+	dirOnly bool
+}
+
+// ParseGitIgnore parses the content of the .gitignore file in dir.
+func ParseGitIgnore(dir string, src []byte) *GitIgnore {
+	ignore := &GitIgnore{Dir: dir}
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !strings.HasSuffix(line, "\\ ") {
+			line = strings.TrimRight(line, " ")
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		var pattern gitIgnorePattern
+		if line[0] == '!' {
+			pattern.negated = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, "\\#") || strings.HasPrefix(line, "\\!") {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		// patterns without slash match in any level, others are relative to the directory
+		if strings.Contains(line, "/") {
+			pattern.glob = strings.TrimPrefix(line, "/")
+		} else {
+			pattern.glob = "**/" + line
+		}
+		ignore.patterns = append(ignore.patterns, pattern)
+	}
+	return ignore
+}
+
+// Match reports whether any pattern matches path, and whether path is ignored by the last matching pattern.
+func (g *GitIgnore) Match(path string, isDir bool) (matched, ignored bool) {
+	rel, err := filepath.Rel(g.Dir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false, false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range g.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if MatchGlob(pattern.glob, rel) {
+			matched, ignored = true, !pattern.negated
+		}
+	}
+	return matched, ignored
+}
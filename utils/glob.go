@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether the slash separated name matches the doublestar pattern, in which a "**" element matches
+// zero or more directories, and other elements are matched by path.Match. A malformed pattern matches nothing.
+func MatchGlob(pattern, name string) bool {
+	return matchGlobElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobElems(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchGlobElems(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if match, err := path.Match(pattern[0], name[0]); err != nil || !match {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ValidateGlob returns an error if the doublestar pattern is malformed.
+func ValidateGlob(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	for _, elem := range strings.Split(pattern, "/") {
+		if _, err := path.Match(elem, ""); err != nil {
+			return fmt.Errorf("invalid pattern %#v: %s", pattern, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMatchGlob(t *testing.T) {
+	Convey("match glob", t, func() {
+		So(MatchGlob("internal/**/mocks", "internal/mocks"), ShouldBeTrue)
+		So(MatchGlob("internal/**/mocks", "internal/a/b/mocks"), ShouldBeTrue)
+		So(MatchGlob("internal/**/mocks", "internal/a/mocks/x.go"), ShouldBeFalse)
+		So(MatchGlob("internal/**", "internal"), ShouldBeTrue)
+		So(MatchGlob("internal/**", "internal/a/x.go"), ShouldBeTrue)
+		So(MatchGlob("**/*.pb.go", "x.pb.go"), ShouldBeTrue)
+		So(MatchGlob("**/*.pb.go", "a/b/x.pb.go"), ShouldBeTrue)
+		So(MatchGlob("*.go", "a/x.go"), ShouldBeFalse)
+		So(MatchGlob("a/[xy].go", "a/y.go"), ShouldBeTrue)
+		So(MatchGlob("a/[", "a/["), ShouldBeFalse)
+
+		So(ValidateGlob("internal/**/*_mock.go"), ShouldBeNil)
+		So(ValidateGlob("a/[/b"), ShouldNotBeNil)
+		So(ValidateGlob(""), ShouldNotBeNil)
+	})
+}
+
+func TestGitIgnore(t *testing.T) {
+	Convey("gitignore", t, func() {
+		dir := filepath.FromSlash("/repo")
+		ignore := ParseGitIgnore(dir, []byte("# comment\n\n*.pb.go\n!keep.pb.go\n/build\nfixtures/\ndocs/*.go\n"))
+		match := func(name string, isDir bool) (bool, bool) {
+			return ignore.Match(filepath.Join(dir, filepath.FromSlash(name)), isDir)
+		}
+
+		matched, ignored := match("a/b/x.pb.go", false)
+		So(matched && ignored, ShouldBeTrue)
+		matched, ignored = match("a/keep.pb.go", false)
+		So(matched, ShouldBeTrue)
+		So(ignored, ShouldBeFalse)
+		_, ignored = match("build", true)
+		So(ignored, ShouldBeTrue)
+		_, ignored = match("a/build", true)
+		So(ignored, ShouldBeFalse)
+		_, ignored = match("a/fixtures", true)
+		So(ignored, ShouldBeTrue)
+		_, ignored = match("a/fixtures", false)
+		So(ignored, ShouldBeFalse)
+		_, ignored = match("docs/x.go", false)
+		So(ignored, ShouldBeTrue)
+		_, ignored = match("a/docs/x.go", false)
+		So(ignored, ShouldBeFalse)
+		matched, _ = ignore.Match(filepath.FromSlash("/other/x.pb.go"), false)
+		So(matched, ShouldBeFalse)
+	})
+}
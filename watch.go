@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nextzhou/goderive/utils"
+)
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	ModTime int64
+	Size    int64
+}
+
+// RunWatch runs once, then keeps polling go files of inputPaths and regenerates packages having changed files.
+// It never returns, errors of each run are reported as the status of regeneration.
+func (d *Derive) RunWatch(inputPaths []string) error {
+	if len(inputPaths) == 0 {
+		inputPaths = []string{"."}
+	}
+	stamps, _ := d.snapshot(inputPaths)
+	d.reportRegeneration(inputPaths, d.Run(inputPaths))
+	for {
+		time.Sleep(d.WatchInterval)
+		newStamps, scannedDirs := d.snapshot(inputPaths)
+		inputs := changedInputs(stamps, newStamps, scannedDirs)
+		stamps = newStamps
+		if len(inputs) == 0 {
+			continue
+		}
+		d.reportRegeneration(inputs, d.Run(inputs))
+	}
+}
+
+func (d *Derive) reportRegeneration(inputs []string, err error) {
+	status := RegenerationStatus{Time: time.Now(), Packages: inputs}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	d.reporter.Regeneration(status)
+}
+
+// snapshot stamps go files of inputPaths, and returns absolute paths of scanned directories.
+// Errors are ignored here, and reported by the following run.
+func (d *Derive) snapshot(inputPaths []string) (map[string]fileStamp, *utils.StrSet) {
+	d.scannedDirs = utils.NewStrSet(0)
+	stamps := make(map[string]fileStamp)
+	for _, path := range inputPaths {
+		files, err := d.ListGoFiles(path, false)
+		if err != nil {
+			continue
+		}
+		// outputs are never listed, writing them doesn't trigger another regeneration
+		for _, file := range files {
+			stat, err := os.Stat(file)
+			if err != nil {
+				continue
+			}
+			stamps[file] = fileStamp{ModTime: stat.ModTime().UnixNano(), Size: stat.Size()}
+		}
+	}
+	return stamps, d.scannedDirs
+}
+
+// changedInputs returns input paths of packages having files added, modified or removed.
+// Scanned directories are regenerated as a whole, otherwise all the listed files in the directory are.
+func changedInputs(oldStamps, newStamps map[string]fileStamp, scannedDirs *utils.StrSet) []string {
+	changedDirs := utils.NewStrSet(0)
+	for file, stamp := range newStamps {
+		if oldStamp, ok := oldStamps[file]; !ok || oldStamp != stamp {
+			changedDirs.Append(filepath.Dir(file))
+		}
+	}
+	for file := range oldStamps {
+		if _, ok := newStamps[file]; !ok {
+			changedDirs.Append(filepath.Dir(file))
+		}
+	}
+
+	inputs := utils.NewStrSet(0)
+	for file := range newStamps {
+		if dir := filepath.Dir(file); changedDirs.Contains(dir) && !isScannedDir(dir, scannedDirs) {
+			inputs.Append(file)
+		}
+	}
+	changedDirs.ForEach(func(dir string) {
+		if isScannedDir(dir, scannedDirs) {
+			inputs.Append(dir)
+		}
+	})
+	ret := inputs.ToSlice()
+	sort.Strings(ret)
+	return ret
+}
+
+func isScannedDir(dir string, scannedDirs *utils.StrSet) bool {
+	absDir, err := filepath.Abs(dir)
+	return err == nil && scannedDirs.Contains(absDir)
+}
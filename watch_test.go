@@ -0,0 +1,48 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nextzhou/goderive/utils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChangedInputs(t *testing.T) {
+	Convey("changed inputs", t, func() {
+		scanned, err := filepath.Abs("a")
+		So(err, ShouldBeNil)
+		scannedDirs := utils.NewStrSetFromSlice([]string{scanned})
+		oldStamps := map[string]fileStamp{
+			"a/x.go": {ModTime: 1, Size: 10},
+			"a/y.go": {ModTime: 1, Size: 10},
+			"b/x.go": {ModTime: 1, Size: 10},
+			"b/y.go": {ModTime: 1, Size: 10},
+			"c/x.go": {ModTime: 1, Size: 10},
+		}
+
+		Convey("unchanged", func() {
+			So(changedInputs(oldStamps, oldStamps, scannedDirs), ShouldBeEmpty)
+		})
+		Convey("modified", func() {
+			newStamps := map[string]fileStamp{
+				"a/x.go": {ModTime: 2, Size: 10},
+				"a/y.go": {ModTime: 1, Size: 10},
+				"b/x.go": {ModTime: 1, Size: 11},
+				"b/y.go": {ModTime: 1, Size: 10},
+				"c/x.go": {ModTime: 1, Size: 10},
+			}
+			So(changedInputs(oldStamps, newStamps, scannedDirs), ShouldResemble, []string{"a", "b/x.go", "b/y.go"})
+		})
+		Convey("added and removed", func() {
+			newStamps := map[string]fileStamp{
+				"a/x.go": {ModTime: 1, Size: 10},
+				"b/x.go": {ModTime: 1, Size: 10},
+				"b/y.go": {ModTime: 1, Size: 10},
+				"c/x.go": {ModTime: 1, Size: 10},
+				"c/z.go": {ModTime: 1, Size: 10},
+			}
+			So(changedInputs(oldStamps, newStamps, scannedDirs), ShouldResemble, []string{"a", "c/x.go", "c/z.go"})
+		})
+	})
+}